@@ -0,0 +1,136 @@
+// Package ast declares the syntax tree of a Fox source file, its JSON
+// form and the ways to walk and rewrite it.
+package ast
+
+import "fox/token"
+
+// Node is any node of the AST. Each one embeds the Span of source it was
+// parsed from.
+type Node interface {
+	NodeSpan() token.Span
+}
+
+// File is a parsed source file.
+type File struct {
+	token.Span
+	PackageName string
+	Imports     []string
+	Types       []TypeDecl
+	Vars        []VarDecl
+	Consts      []ConstDecl
+	Funcs       []FuncDecl
+	Methods     map[string]MethodSet
+	BadDecls    []BadDecl
+}
+
+// BadDecl stands in for a top-level declaration with a syntax error.
+type BadDecl struct {
+	token.Span
+}
+
+// type T U or a type ( ... ) group
+type TypeDecl struct {
+	token.Span
+	Grouped bool
+	Specs   []TypeSpec
+}
+
+// TypeSpec is one line of a type declaration: a defined type T U, or an
+// alias T = U when Alias is set.
+type TypeSpec struct {
+	token.Span
+	Name  string
+	Alias bool
+	Type  TypeExpr
+}
+
+// FieldDecl is one line of a struct: a b T, or an embedded field T or *T
+// with no Names. Tag is the decoded tag string, if any.
+type FieldDecl struct {
+	token.Span
+	Names    []string
+	Type     TypeExpr
+	Embedded bool
+	Tag      string
+}
+
+// var x T, var x = v or a var ( ... ) group; also a statement
+type VarDecl struct {
+	token.Span
+	Grouped bool
+	Specs   []ValueSpec
+}
+
+func (VarDecl) isStatement() {}
+
+// const x = v or a const ( ... ) group; also a statement
+type ConstDecl struct {
+	token.Span
+	Grouped bool
+	Specs   []ValueSpec
+}
+
+func (ConstDecl) isStatement() {}
+
+// ValueSpec is one line of a var or const declaration. Type is nil when
+// inferred from Values, Values is empty for a zero-valued var.
+//
+// In a const group Iota is the line's index, the value of iota on it. A
+// line with neither type nor values repeats the previous line's; those
+// are copied in and Implicit is set.
+type ValueSpec struct {
+	token.Span
+	Names    []string
+	Type     TypeExpr
+	Values   []Expression
+	Iota     int
+	Implicit bool
+}
+
+// FuncDecl is a function, or a method when Recv is set.
+type FuncDecl struct {
+	token.Span
+	Recv    *ParamDecl
+	Name    string
+	NamePos token.Position
+	Params  []ParamDecl
+	Returns []ReturnSig
+	Body    []Statement
+}
+
+// SignatureParams returns the parameters that appear in f's signature,
+// the receiver first. The external-pointer rule treats a receiver like
+// any other parameter: a method may touch what it was handed through it.
+func (f *FuncDecl) SignatureParams() []ParamDecl {
+	if f.Recv == nil {
+		return f.Params
+	}
+	return append([]ParamDecl{*f.Recv}, f.Params...)
+}
+
+// ResultType is the type of a call to f: nil without results, the type
+// itself for a single unnamed one, and a TupleType otherwise.
+func (f *FuncDecl) ResultType() TypeExpr {
+	switch {
+	case len(f.Returns) == 0:
+		return nil
+	case len(f.Returns) == 1 && f.Returns[0].Name == "":
+		return f.Returns[0].Type
+	}
+	last := f.Returns[len(f.Returns)-1]
+	return TupleType{Span: token.Span{Pos: f.Returns[0].Pos, End: last.End}, Elems: f.Returns}
+}
+
+type ParamDecl struct {
+	token.Span
+	Name string
+	Type TypeExpr
+}
+
+// ReturnSig is one result of a function; Name is empty unless the
+// results are named.
+type ReturnSig struct {
+	token.Span
+	Name string
+	Type TypeExpr
+}
@@ -0,0 +1,129 @@
+package ast
+
+import "fox/token"
+
+// -x, +x, !x, ^x, &x or *p
+type UnaryExpr struct {
+	token.Span
+	Op   token.Token
+	Expr Expression
+}
+
+type Expression interface {
+	Node
+	isExpr()
+}
+
+type NumberExpr struct {
+	token.Span
+	Literal string
+	Suffix  string // "u8", "f32", ... or empty
+}
+
+func (NumberExpr) isExpr() {}
+
+type StringExpr struct {
+	token.Span
+	Literal string
+}
+
+func (StringExpr) isExpr() {}
+
+type RuneExpr struct {
+	token.Span
+	Literal string
+}
+
+func (RuneExpr) isExpr() {}
+
+type IdentExpr struct {
+	token.Span
+	Name string
+}
+
+func (IdentExpr) isExpr() {}
+
+type BinaryExpr struct {
+	token.Span
+	Op    token.Token
+	Left  Expression
+	Right Expression
+}
+
+func (BinaryExpr) isExpr() {}
+
+func (UnaryExpr) isExpr() {}
+
+// Func(Args); Func is a name, a method value such as s.lock.Lock or any
+// other expression yielding a function
+type CallExpr struct {
+	token.Span
+	Func Expression
+	Args []Expression
+}
+
+func (CallExpr) isExpr() {}
+
+// (X)
+type ParenExpr struct {
+	token.Span
+	X Expression
+}
+
+func (ParenExpr) isExpr() {}
+
+// BadExpr stands in for an expression with a syntax error.
+type BadExpr struct {
+	token.Span
+}
+
+func (BadExpr) isExpr() {}
+
+// x.Sel
+type SelectorExpr struct {
+	token.Span
+	X   Expression
+	Sel string
+}
+
+func (SelectorExpr) isExpr() {}
+
+// x[Index]
+type IndexExpr struct {
+	token.Span
+	X     Expression
+	Index Expression
+}
+
+func (IndexExpr) isExpr() {}
+
+// x[Low:High] or x[Low:High:Max]; missing bounds are nil
+type SliceExpr struct {
+	token.Span
+	X      Expression
+	Low    Expression
+	High   Expression
+	Max    Expression
+	Slice3 bool
+}
+
+func (SliceExpr) isExpr() {}
+
+// Type{Elts}; Type is nil for an elided inner literal such as the
+// {1, 2} in Pair{{1, 2}, {3, 4}}
+type CompositeLit struct {
+	token.Span
+	Type Expression
+	Elts []Expression
+}
+
+func (CompositeLit) isExpr() {}
+
+// Key: Value inside a composite literal
+type KeyValueExpr struct {
+	token.Span
+	Key   Expression
+	Value Expression
+}
+
+func (KeyValueExpr) isExpr() {}
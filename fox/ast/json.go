@@ -0,0 +1,366 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"fox/token"
+)
+
+// ================= JSON AST format =================
+//
+// The AST is written as {"version": N, "ast": {...}}. Every node is an
+// object whose first member is "kind", the name of its Go type, followed
+// by its fields; the embedded Span appears as "Pos" and "End". Tokens,
+// positions and other plain values keep their encoding/json form, except
+// that a string which is not valid UTF-8, such as a literal with a \xff
+// escape, is written as {"base64": "..."} of its bytes. A nil node,
+// pointer or slice is null, so decoding gives back an equal AST.
+
+// SchemaVersion changes whenever a node gains, loses or renames a field,
+// so readers can reject a format they do not know. Version 2 renamed the
+// root node from AST to File, version 3 added {"base64": ...} strings.
+const SchemaVersion = 3
+
+// nodeKinds maps each "kind" to its node type.
+var nodeKinds = map[string]reflect.Type{}
+
+func init() {
+	for _, n := range []Node{
+		File{}, BadDecl{}, TypeDecl{}, TypeSpec{}, FieldDecl{}, VarDecl{},
+		ConstDecl{}, ValueSpec{}, FuncDecl{}, ParamDecl{}, ReturnSig{},
+
+		BreakNode{}, ContinueNode{}, FallthroughStmt{}, LabeledStmt{},
+		ReturnStmt{}, IfStmt{}, SwitchStmt{}, CaseClause{}, ForStmt{},
+		RangeStmt{}, AssignStmt{}, DefineStmt{}, BadStmt{}, ExprStmt{},
+		IncDecStmt{},
+
+		UnaryExpr{}, NumberExpr{}, StringExpr{}, RuneExpr{}, IdentExpr{},
+		BinaryExpr{}, CallExpr{}, ParenExpr{}, BadExpr{}, SelectorExpr{},
+		IndexExpr{}, SliceExpr{}, CompositeLit{}, KeyValueExpr{},
+
+		NamedType{}, QualifiedType{}, StructType{}, PointerType{},
+		ArrayType{}, SliceType{}, MapType{}, FuncType{}, TupleType{},
+		OwnType{},
+	} {
+		t := reflect.TypeOf(n)
+		nodeKinds[t.Name()] = t
+	}
+}
+
+var spanType = reflect.TypeOf(token.Span{})
+
+func isNodeType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && nodeKinds[t.Name()] == t
+}
+
+// Marshal encodes f in the versioned JSON format.
+func Marshal(f *File) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"version":%d,"ast":`, SchemaVersion)
+	if err := encodeValue(&buf, reflect.ValueOf(*f)); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return encodeValue(buf, v.Elem())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	case reflect.Struct:
+		if isNodeType(v.Type()) {
+			return encodeNode(buf, v)
+		}
+		return encodeStruct(buf, v)
+
+	case reflect.String:
+		if s := v.String(); !utf8.ValidString(s) {
+			buf.WriteString(`{"base64":`)
+			if err := encodeLeaf(buf, base64.StdEncoding.EncodeToString([]byte(s))); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+			return nil
+		}
+	}
+	return encodeLeaf(buf, v.Interface())
+}
+
+// encodeStruct writes a struct other than a node, such as a token, the
+// way encoding/json would, so that the strings in it get the same care.
+func encodeStruct(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	buf.WriteByte('{')
+	first := true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		fmt.Fprintf(buf, "%q:", f.Name)
+		if err := encodeValue(buf, v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeNode(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	fmt.Fprintf(buf, `{"kind":%q`, t.Name())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type == spanType {
+			span := v.Field(i).Interface().(token.Span)
+			buf.WriteString(`,"Pos":`)
+			if err := encodeLeaf(buf, span.Pos); err != nil {
+				return err
+			}
+			buf.WriteString(`,"End":`)
+			if err := encodeLeaf(buf, span.End); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(buf, ",%q:", f.Name)
+		if err := encodeValue(buf, v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// encodeLeaf writes a value that holds no nodes, leaving <, > and &
+// readable.
+func encodeLeaf(buf *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // Encode's newline
+	return nil
+}
+
+// Unmarshal decodes the versioned JSON format back into a File. It fails
+// on an unknown version, kind or field.
+func Unmarshal(data []byte) (*File, error) {
+	var doc struct {
+		Version int
+		AST     json.RawMessage
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported AST schema version %d, want %d", doc.Version, SchemaVersion)
+	}
+
+	f := &File{}
+	if err := decodeValue(doc.AST, reflect.ValueOf(f).Elem(), "ast"); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// decodeValue decodes data into v; path names v in error messages.
+func decodeValue(data json.RawMessage, v reflect.Value, path string) error {
+	isNull := bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if isNull {
+			return nil
+		}
+		var head struct{ Kind string }
+		if err := json.Unmarshal(data, &head); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		t, ok := nodeKinds[head.Kind]
+		if !ok {
+			return fmt.Errorf("%s: unknown node kind %q", path, head.Kind)
+		}
+		if !t.Implements(v.Type()) {
+			return fmt.Errorf("%s: %s is not a %s", path, head.Kind, v.Type().Name())
+		}
+		node := reflect.New(t).Elem()
+		if err := decodeNode(data, node, path); err != nil {
+			return err
+		}
+		v.Set(node)
+		return nil
+
+	case reflect.Pointer:
+		if isNull {
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(data, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+
+	case reflect.Slice:
+		if isNull {
+			return nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, s.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+
+	case reflect.Struct:
+		if isNodeType(v.Type()) {
+			return decodeNode(data, v, path)
+		}
+		return decodeStruct(data, v, path)
+
+	case reflect.String:
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			var enc struct{ Base64 string }
+			if err := json.Unmarshal(data, &enc); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			b, err := base64.StdEncoding.DecodeString(enc.Base64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.SetString(string(b))
+			return nil
+		}
+	}
+
+	if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func decodeNode(data json.RawMessage, v reflect.Value, path string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t := v.Type()
+	if kind := strings.Trim(string(fields["kind"]), `"`); kind != t.Name() {
+		return fmt.Errorf("%s: got kind %q, want %s", path, kind, t.Name())
+	}
+	delete(fields, "kind")
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type == spanType {
+			span := v.Field(i)
+			for _, name := range []string{"Pos", "End"} {
+				if raw, ok := fields[name]; ok {
+					if err := decodeValue(raw, span.FieldByName(name), path+"."+name); err != nil {
+						return err
+					}
+					delete(fields, name)
+				}
+			}
+			continue
+		}
+		raw, ok := fields[f.Name]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(raw, v.Field(i), path+"."+f.Name); err != nil {
+			return err
+		}
+		delete(fields, f.Name)
+	}
+
+	for name := range fields {
+		return fmt.Errorf("%s: unknown field %q in %s", path, name, t.Name())
+	}
+	return nil
+}
+
+// decodeStruct decodes a struct other than a node field by field.
+func decodeStruct(data json.RawMessage, v reflect.Value, path string) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		raw, ok := fields[f.Name]
+		if !ok || !f.IsExported() {
+			continue
+		}
+		if err := decodeValue(raw, v.Field(i), path+"."+f.Name); err != nil {
+			return err
+		}
+		delete(fields, f.Name)
+	}
+
+	for name := range fields {
+		return fmt.Errorf("%s: unknown field %q in %s", path, name, t.Name())
+	}
+	return nil
+}
+
+// Fprint writes f to w as indented JSON.
+func Fprint(w io.Writer, f *File) error {
+	data, err := Marshal(f)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err = w.Write(out.Bytes())
+	return err
+}
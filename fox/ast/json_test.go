@@ -0,0 +1,48 @@
+package ast_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"fox/ast"
+	"fox/parser"
+)
+
+func roundTrip(t *testing.T, name string, src []byte) {
+	t.Helper()
+	file, err := parser.ParseFile(name, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ast.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ast.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, file) {
+		t.Errorf("%s: AST changed in a JSON round trip", name)
+	}
+}
+
+func TestRoundTripTestFox(t *testing.T) {
+	src, err := os.ReadFile("../../test.fox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, "test.fox", src)
+}
+
+func TestRoundTripInvalidUTF8(t *testing.T) {
+	src := "package p\n\nfunc f() {\n\ts := \"a\\\"b\\n\\xff\"\n\tr := '\\xfe'\n\tx := a + /* \xff */ b\n}\n"
+	roundTrip(t, "utf8.fox", []byte(src))
+}
+
+func TestUnmarshalVersion(t *testing.T) {
+	if _, err := ast.Unmarshal([]byte(`{"version":2,"ast":{"kind":"File"}}`)); err == nil {
+		t.Error("version 2 accepted")
+	}
+}
@@ -0,0 +1,111 @@
+package ast
+
+import (
+	"unicode/utf8"
+
+	"fox/token"
+)
+
+// ================= Method sets =================
+
+// MethodSet holds the method names of a defined type T. Value lists the
+// methods declared on T, Pointer those callable through a *T, which as
+// in Go is all of them.
+type MethodSet struct {
+	Value   []string
+	Pointer []string
+}
+
+// RecvBase splits a receiver type T or *T into the name T and whether
+// the receiver is a pointer. The name is empty for any other type.
+func RecvBase(typ TypeExpr) (string, bool) {
+	ptr := false
+	if p, ok := typ.(PointerType); ok {
+		typ, ptr = p.Elem, true
+	}
+	if n, ok := typ.(NamedType); ok {
+		return n.Name, ptr
+	}
+	return "", ptr
+}
+
+// LookupMethod finds the method name in the method set of typ, a defined
+// type T or *T. Through a T only value-receiver methods are found.
+func (file *File) LookupMethod(typ TypeExpr, name string) (*FuncDecl, bool) {
+	types := file.TypeSpecs()
+	base, ptr := RecvBase(typ)
+	spec, ok := ResolveAlias(types, base)
+	if !ok {
+		return nil, false
+	}
+	for i := range file.Funcs {
+		f := &file.Funcs[i]
+		if f.Recv == nil || f.Name != name {
+			continue
+		}
+		recv, recvPtr := RecvBase(f.Recv.Type)
+		if r, ok := ResolveAlias(types, recv); ok && r == spec && (ptr || !recvPtr) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// TypeSpecs indexes the file's type declarations by name.
+func (file *File) TypeSpecs() map[string]*TypeSpec {
+	types := map[string]*TypeSpec{}
+	for i := range file.Types {
+		for j := range file.Types[i].Specs {
+			spec := &file.Types[i].Specs[j]
+			types[spec.Name] = spec
+		}
+	}
+	return types
+}
+
+// ResolveAlias follows aliases from name to the defined type they stand
+// for. It fails for undeclared names and aliases of types from elsewhere.
+func ResolveAlias(types map[string]*TypeSpec, name string) (*TypeSpec, bool) {
+	// at most one step per declared type, so alias cycles end
+	for range len(types) + 1 {
+		spec, ok := types[name]
+		if !ok {
+			return nil, false
+		}
+		if !spec.Alias {
+			return spec, true
+		}
+		target, ok := spec.Type.(NamedType)
+		if !ok {
+			return nil, false
+		}
+		name = target.Name
+	}
+	return nil, false
+}
+
+// HasField reports whether st has a field called name, embedded fields
+// included.
+func (st StructType) HasField(name string) bool {
+	for _, field := range st.Fields {
+		if field.Embedded {
+			if embedded, _ := EmbeddedName(field.Type); embedded == name {
+				return true
+			}
+		}
+		for _, n := range field.Names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NameSpan is the span of f's name.
+func (f *FuncDecl) NameSpan() token.Span {
+	end := f.NamePos
+	end.Offset += len(f.Name)
+	end.Column += utf8.RuneCountInString(f.Name)
+	return token.Span{Pos: f.NamePos, End: end}
+}
@@ -0,0 +1,148 @@
+package ast
+
+import "fox/token"
+
+// Interfaces
+
+type Statement interface {
+	Node
+	isStatement()
+}
+
+// AST Nodes (Statements)
+
+// break or break Label
+type BreakNode struct {
+	token.Span
+	Tok   token.Token
+	Label string
+}
+
+func (BreakNode) isStatement() {}
+
+// continue or continue Label
+type ContinueNode struct {
+	token.Span
+	Tok   token.Token
+	Label string
+}
+
+func (ContinueNode) isStatement() {}
+
+type FallthroughStmt struct {
+	token.Span
+	Tok token.Token
+}
+
+func (FallthroughStmt) isStatement() {}
+
+// Label: Stmt
+type LabeledStmt struct {
+	token.Span
+	Label string
+	Stmt  Statement
+}
+
+func (LabeledStmt) isStatement() {}
+
+type ReturnStmt struct {
+	token.Span
+	RetValues []Expression
+}
+
+func (ReturnStmt) isStatement() {}
+
+// if Cond { Then } else { Else }, or else ElseIf for an else-if chain
+type IfStmt struct {
+	token.Span
+	Cond   Expression
+	Then   []Statement
+	Else   []Statement
+	ElseIf *IfStmt
+}
+
+func (IfStmt) isStatement() {}
+
+// switch Tag { Cases }; Tag is nil in a tagless switch, where each case
+// is a condition
+type SwitchStmt struct {
+	token.Span
+	Tag   Expression
+	Cases []CaseClause
+}
+
+func (SwitchStmt) isStatement() {}
+
+// case List: Body, or default: Body when Default is set
+type CaseClause struct {
+	token.Span
+	List    []Expression
+	Default bool
+	Body    []Statement
+}
+
+type ForStmt struct {
+	token.Span
+	Init Statement
+	Cond Expression
+	Post Statement
+	Body []Statement
+}
+
+func (ForStmt) isStatement() {}
+
+// for Key, Value := range X { Body }, with = when Define is unset. Key and
+// Value are nil when left out; X is an array, slice, string or count.
+type RangeStmt struct {
+	token.Span
+	Key    Expression
+	Value  Expression
+	Define bool
+	X      Expression
+	Body   []Statement
+}
+
+func (RangeStmt) isStatement() {}
+
+// Lhs = Rhs; Lhs holds idents, x.f, x[i], *p or _. With more than one
+// target and a single call on the right, the call's results are unpacked.
+type AssignStmt struct {
+	token.Span
+	Lhs []Expression
+	Op  string // "=", "+=", "-=", ...
+	Rhs []Expression
+}
+
+func (AssignStmt) isStatement() {}
+
+// Lhs := Rhs; every Lhs is an IdentExpr, possibly _
+type DefineStmt struct {
+	token.Span
+	Lhs []Expression
+	Rhs []Expression
+}
+
+func (DefineStmt) isStatement() {}
+
+// BadStmt stands in for a statement with a syntax error.
+type BadStmt struct {
+	token.Span
+}
+
+func (BadStmt) isStatement() {}
+
+type ExprStmt struct {
+	token.Span
+	Expr Expression
+}
+
+func (ExprStmt) isStatement() {}
+
+// x++ or x--
+type IncDecStmt struct {
+	token.Span
+	X  Expression
+	Op string
+}
+
+func (IncDecStmt) isStatement() {}
@@ -0,0 +1,165 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"fox/token"
+)
+
+// ================= Type expressions =================
+
+type TypeExpr interface {
+	Node
+	isType()
+}
+
+// T, including predeclared types such as int and the FFI type cstr
+type NamedType struct {
+	token.Span
+	Name string
+}
+
+func (NamedType) isType() {}
+
+// pkg.T
+type QualifiedType struct {
+	token.Span
+	Pkg  string
+	Name string
+}
+
+func (QualifiedType) isType() {}
+
+// struct { Fields }
+type StructType struct {
+	token.Span
+	Fields []FieldDecl
+}
+
+func (StructType) isType() {}
+
+// *Elem
+type PointerType struct {
+	token.Span
+	Elem TypeExpr
+}
+
+func (PointerType) isType() {}
+
+// [Len]Elem
+type ArrayType struct {
+	token.Span
+	Len  Expression
+	Elem TypeExpr
+}
+
+func (ArrayType) isType() {}
+
+// []Elem
+type SliceType struct {
+	token.Span
+	Elem TypeExpr
+}
+
+func (SliceType) isType() {}
+
+// map[Key]Value
+type MapType struct {
+	token.Span
+	Key   TypeExpr
+	Value TypeExpr
+}
+
+func (MapType) isType() {}
+
+// func(Params) Results; parameter names are optional
+type FuncType struct {
+	token.Span
+	Params  []ParamDecl
+	Results []ReturnSig
+}
+
+func (FuncType) isType() {}
+
+// TupleType is the type of a call returning several results.
+type TupleType struct {
+	token.Span
+	Elems []ReturnSig
+}
+
+func (TupleType) isType() {}
+
+// own[Elem] is memory handed over by foreign code, which the holder must
+// free, as in extern func strdup(s cstr) *own[byte].
+type OwnType struct {
+	token.Span
+	Elem TypeExpr
+}
+
+func (OwnType) isType() {}
+
+// EmbeddedName is the implicit field name of an embedded field type
+// T, *T, pkg.T or *pkg.T, and whether the field is a pointer. The name is
+// empty for any other type.
+func EmbeddedName(typ TypeExpr) (string, bool) {
+	ptr := false
+	if p, ok := typ.(PointerType); ok {
+		typ, ptr = p.Elem, true
+	}
+	switch t := typ.(type) {
+	case NamedType:
+		return t.Name, ptr
+	case QualifiedType:
+		return t.Name, ptr
+	}
+	return "", ptr
+}
+
+// TypeString spells t the way it is written in source.
+func TypeString(t TypeExpr) string {
+	switch t := t.(type) {
+	case NamedType:
+		return t.Name
+	case QualifiedType:
+		return t.Pkg + "." + t.Name
+	case PointerType:
+		return "*" + TypeString(t.Elem)
+	case ArrayType:
+		n := "..."
+		if lit, ok := t.Len.(NumberExpr); ok {
+			n = lit.Literal + lit.Suffix
+		}
+		return "[" + n + "]" + TypeString(t.Elem)
+	case SliceType:
+		return "[]" + TypeString(t.Elem)
+	case MapType:
+		return "map[" + TypeString(t.Key) + "]" + TypeString(t.Value)
+	case OwnType:
+		return "own[" + TypeString(t.Elem) + "]"
+	case StructType:
+		return "struct{...}"
+	case FuncType:
+		params := make([]string, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = strings.TrimSpace(p.Name + " " + TypeString(p.Type))
+		}
+		s := "func(" + strings.Join(params, ", ") + ")"
+		if len(t.Results) > 0 {
+			s += " " + TypeString(TupleType{Elems: t.Results})
+		}
+		return s
+	case TupleType:
+		elems := make([]string, len(t.Elems))
+		for i, r := range t.Elems {
+			elems[i] = strings.TrimSpace(r.Name + " " + TypeString(r.Type))
+		}
+		if len(elems) == 1 && t.Elems[0].Name == "" {
+			return elems[0]
+		}
+		return "(" + strings.Join(elems, ", ") + ")"
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%T", t)
+}
@@ -0,0 +1,416 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ================= Traversal =================
+
+// A Visitor's Visit method is called for each node found by Walk. If it
+// returns a non-nil w, Walk visits the node's children with w and then
+// calls w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node in depth-first order, children
+// in the order of their struct fields. Nil children are skipped.
+func Walk(v Visitor, node Node) {
+	if node == nil {
+		return
+	}
+	if f, ok := node.(*File); ok {
+		node = *f
+	}
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// declarations
+	case File:
+		walkList(v, n.Types)
+		walkList(v, n.Vars)
+		walkList(v, n.Consts)
+		walkList(v, n.Funcs)
+		walkList(v, n.BadDecls)
+	case BadDecl:
+	case TypeDecl:
+		walkList(v, n.Specs)
+	case TypeSpec:
+		Walk(v, n.Type)
+	case FieldDecl:
+		Walk(v, n.Type)
+	case VarDecl:
+		walkList(v, n.Specs)
+	case ConstDecl:
+		walkList(v, n.Specs)
+	case ValueSpec:
+		Walk(v, n.Type)
+		walkList(v, n.Values)
+	case FuncDecl:
+		if n.Recv != nil {
+			Walk(v, *n.Recv)
+		}
+		walkList(v, n.Params)
+		walkList(v, n.Returns)
+		walkList(v, n.Body)
+	case ParamDecl:
+		Walk(v, n.Type)
+	case ReturnSig:
+		Walk(v, n.Type)
+
+	// statements
+	case BreakNode, ContinueNode, FallthroughStmt, BadStmt:
+	case LabeledStmt:
+		Walk(v, n.Stmt)
+	case ReturnStmt:
+		walkList(v, n.RetValues)
+	case IfStmt:
+		Walk(v, n.Cond)
+		walkList(v, n.Then)
+		walkList(v, n.Else)
+		if n.ElseIf != nil {
+			Walk(v, *n.ElseIf)
+		}
+	case SwitchStmt:
+		Walk(v, n.Tag)
+		walkList(v, n.Cases)
+	case CaseClause:
+		walkList(v, n.List)
+		walkList(v, n.Body)
+	case ForStmt:
+		Walk(v, n.Init)
+		Walk(v, n.Cond)
+		Walk(v, n.Post)
+		walkList(v, n.Body)
+	case RangeStmt:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+		Walk(v, n.X)
+		walkList(v, n.Body)
+	case AssignStmt:
+		walkList(v, n.Lhs)
+		walkList(v, n.Rhs)
+	case DefineStmt:
+		walkList(v, n.Lhs)
+		walkList(v, n.Rhs)
+	case ExprStmt:
+		Walk(v, n.Expr)
+	case IncDecStmt:
+		Walk(v, n.X)
+
+	// expressions
+	case NumberExpr, StringExpr, RuneExpr, IdentExpr, BadExpr:
+	case UnaryExpr:
+		Walk(v, n.Expr)
+	case BinaryExpr:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case CallExpr:
+		Walk(v, n.Func)
+		walkList(v, n.Args)
+	case ParenExpr:
+		Walk(v, n.X)
+	case SelectorExpr:
+		Walk(v, n.X)
+	case IndexExpr:
+		Walk(v, n.X)
+		Walk(v, n.Index)
+	case SliceExpr:
+		Walk(v, n.X)
+		Walk(v, n.Low)
+		Walk(v, n.High)
+		Walk(v, n.Max)
+	case CompositeLit:
+		Walk(v, n.Type)
+		walkList(v, n.Elts)
+	case KeyValueExpr:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+
+	// types
+	case NamedType, QualifiedType:
+	case StructType:
+		walkList(v, n.Fields)
+	case PointerType:
+		Walk(v, n.Elem)
+	case ArrayType:
+		Walk(v, n.Len)
+		Walk(v, n.Elem)
+	case SliceType:
+		Walk(v, n.Elem)
+	case MapType:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+	case FuncType:
+		walkList(v, n.Params)
+		walkList(v, n.Results)
+	case TupleType:
+		walkList(v, n.Elems)
+	case OwnType:
+		Walk(v, n.Elem)
+
+	default:
+		panic(fmt.Sprintf("Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkList[N Node](v Visitor, list []N) {
+	for _, n := range list {
+		Walk(v, n)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree rooted at node like Walk, calling f for each
+// node and then f(nil) once its children are done. If f returns false
+// the node's children are skipped.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// ================= Rewriting =================
+
+// An ApplyFunc is called by Apply for each node. Returning false from
+// the pre-order call skips the node's children and its post-order call;
+// returning false from the post-order call stops the traversal.
+type ApplyFunc func(*Cursor) bool
+
+// A Cursor describes the node being visited by Apply and lets the
+// ApplyFunc replace it, delete it or insert nodes around it.
+type Cursor struct {
+	parent  Node
+	name    string
+	list    reflect.Value // the slice holding the node, if any
+	iter    *iterator
+	field   reflect.Value // the field holding the node, if not in a slice
+	deleted bool
+}
+
+type iterator struct {
+	index, step int
+}
+
+// Node returns the current node, after any Replace.
+func (c *Cursor) Node() Node {
+	return slotNode(c.slot())
+}
+
+// Parent returns the node containing the current one, as it was before
+// its children were visited.
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Name returns the name of the parent's field holding the current node,
+// such as "Body" or "Cond".
+func (c *Cursor) Name() string { return c.name }
+
+// Index returns the position of the current node in its parent's slice
+// field, or -1 if the field is not a slice.
+func (c *Cursor) Index() int {
+	if c.iter == nil {
+		return -1
+	}
+	return c.iter.index
+}
+
+// Replace replaces the current node with n, which must fit the field: a
+// Statement in a Body, a TypeExpr in a Type and so on. The children of n
+// are visited instead of those of the old node.
+func (c *Cursor) Replace(n Node) {
+	slot := c.slot()
+	v, ok := fitSlot(slot.Type(), n)
+	if !ok {
+		panic(fmt.Sprintf("Replace: %T cannot be stored in %s", n, c.name))
+	}
+	slot.Set(v)
+}
+
+// Delete removes the current node from its slice. The node's children
+// are not visited.
+func (c *Cursor) Delete() {
+	i := c.listIndex("Delete")
+	c.list.Set(reflect.AppendSlice(c.list.Slice(0, i), c.list.Slice(i+1, c.list.Len())))
+	c.iter.step--
+	c.deleted = true
+}
+
+// InsertBefore inserts n before the current node in its slice, for
+// example a synthetic free ahead of a return. Apply does not visit n.
+func (c *Cursor) InsertBefore(n Node) {
+	c.insert("InsertBefore", c.listIndex("InsertBefore"), n)
+	c.iter.index++
+}
+
+// InsertAfter inserts n after the current node in its slice. Apply does
+// not visit n.
+func (c *Cursor) InsertAfter(n Node) {
+	c.insert("InsertAfter", c.listIndex("InsertAfter")+1, n)
+	c.iter.step++
+}
+
+func (c *Cursor) listIndex(op string) int {
+	if c.iter == nil {
+		panic(op + ": node is not in a slice")
+	}
+	if c.deleted {
+		panic(op + ": node was deleted")
+	}
+	return c.iter.index
+}
+
+func (c *Cursor) insert(op string, i int, n Node) {
+	v, ok := fitSlot(c.list.Type().Elem(), n)
+	if !ok {
+		panic(fmt.Sprintf("%s: %T cannot be stored in %s", op, n, c.name))
+	}
+	tail := reflect.MakeSlice(c.list.Type(), 0, c.list.Len()-i+1)
+	tail = reflect.Append(tail, v)
+	tail = reflect.AppendSlice(tail, c.list.Slice(i, c.list.Len()))
+	c.list.Set(reflect.AppendSlice(c.list.Slice(0, i), tail))
+}
+
+func (c *Cursor) slot() reflect.Value {
+	if c.iter != nil {
+		return c.list.Index(c.iter.index)
+	}
+	return c.field
+}
+
+// Apply traverses the tree rooted at root in the order of Walk, calling
+// pre before and post after a node's children; either may be nil. Nodes
+// are values, so edits are made to copies: root itself is left unchanged
+// and the rewritten tree is returned.
+func Apply(root Node, pre, post ApplyFunc) Node {
+	holder := struct{ Root Node }{root}
+	a := &applier{pre: pre, post: post}
+	a.apply(nil, "Root", Cursor{field: reflect.ValueOf(&holder).Elem().Field(0)})
+	return holder.Root
+}
+
+type applier struct {
+	pre, post ApplyFunc
+	stopped   bool
+}
+
+func (a *applier) apply(parent Node, name string, c Cursor) {
+	c.parent, c.name = parent, name
+	if a.stopped || c.Node() == nil {
+		return
+	}
+	if a.pre != nil && !a.pre(&c) {
+		return
+	}
+	if c.deleted {
+		return
+	}
+	node := c.Node()
+	if node == nil {
+		return
+	}
+
+	// visit the children of a private copy, then store it back
+	cp := reflect.New(reflect.TypeOf(node)).Elem()
+	cp.Set(reflect.ValueOf(node))
+	copyLists(cp)
+	a.children(node, cp)
+	slot := c.slot()
+	if slot.Kind() == reflect.Pointer || slot.Kind() == reflect.Interface && slot.Elem().Kind() == reflect.Pointer {
+		slot.Set(cp.Addr())
+	} else {
+		slot.Set(cp)
+	}
+
+	if a.post != nil && !a.post(&c) {
+		a.stopped = true
+	}
+}
+
+// copyLists gives the node struct v its own copy of each slice of
+// children, so they can be edited without touching the original.
+func copyLists(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Slice && !f.IsNil() && isChild(f.Type().Elem()) {
+			list := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+			reflect.Copy(list, f)
+			f.Set(list)
+		}
+	}
+}
+
+// children visits each child field of v, an addressable copy of parent.
+func (a *applier) children(parent Node, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		name := t.Field(i).Name
+		switch {
+		case f.Kind() == reflect.Slice && isChild(f.Type().Elem()):
+			iter := &iterator{}
+			for iter.index = 0; iter.index < f.Len(); iter.index += iter.step {
+				iter.step = 1
+				a.apply(parent, name, Cursor{list: f, iter: iter})
+			}
+		case isChild(f.Type()):
+			a.apply(parent, name, Cursor{field: f})
+		}
+	}
+}
+
+var nodeType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// isChild reports whether a field of type t holds a node: an interface
+// such as Expression, a node struct or a pointer to one.
+func isChild(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return t.Implements(nodeType)
+	case reflect.Pointer:
+		return isNodeType(t.Elem())
+	}
+	return isNodeType(t)
+}
+
+// slotNode returns the node held in v, or nil. A node behind a pointer,
+// such as IfStmt.ElseIf, is returned by value as Walk would pass it.
+func slotNode(v reflect.Value) Node {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface().(Node)
+}
+
+// fitSlot converts n for storing in a field of type t, taking the address
+// of a struct node when t is a pointer to it.
+func fitSlot(t reflect.Type, n Node) (reflect.Value, bool) {
+	if n == nil {
+		if t.Kind() == reflect.Interface || t.Kind() == reflect.Pointer {
+			return reflect.Zero(t), true
+		}
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(n)
+	if v.Type().AssignableTo(t) {
+		return v, true
+	}
+	if t.Kind() == reflect.Pointer && v.Type() == t.Elem() {
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, true
+	}
+	return reflect.Value{}, false
+}
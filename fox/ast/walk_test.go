@@ -0,0 +1,249 @@
+package ast_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"fox/ast"
+	"fox/parser"
+)
+
+const walkSrc = `package p
+
+func f(a int) int {
+	x := a + 1
+	drop(x)
+	if x > 1 {
+		return x
+	} else if x > 0 {
+		return a
+	}
+	return 0
+}
+`
+
+func parseWalkSrc(t *testing.T) *ast.File {
+	t.Helper()
+	file, err := parser.ParseFile("walk.fox", []byte(walkSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// applyTo runs Apply on file and checks that file itself is untouched.
+func applyTo(t *testing.T, file *ast.File, pre, post ast.ApplyFunc) *ast.File {
+	t.Helper()
+	before, err := ast.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ast.Apply(file, pre, post).(*ast.File)
+	after, err := ast.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("Apply changed its input")
+	}
+	return got
+}
+
+// idents lists the names of the identifiers under n in Walk order.
+func idents(n ast.Node) []string {
+	var names []string
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(ast.IdentExpr); ok {
+			names = append(names, id.Name)
+		}
+		return true
+	})
+	return names
+}
+
+// kinds lists the types of the statements in body.
+func kinds(body []ast.Statement) []string {
+	var list []string
+	for _, s := range body {
+		list = append(list, reflect.TypeOf(s).Name())
+	}
+	return list
+}
+
+func call(name string) ast.Statement {
+	return ast.ExprStmt{Expr: ast.CallExpr{Func: ast.IdentExpr{Name: name}}}
+}
+
+func TestInspect(t *testing.T) {
+	file := parseWalkSrc(t)
+	want := []string{"x", "a", "drop", "x", "x", "x", "x", "a"}
+	if got := idents(file); !reflect.DeepEqual(got, want) {
+		t.Errorf("idents %q, want %q", got, want)
+	}
+
+	// every node is followed by f(nil) once its children are done
+	depth := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			depth--
+		} else {
+			depth++
+		}
+		return true
+	})
+	if depth != 0 {
+		t.Errorf("depth %d after Inspect, want 0", depth)
+	}
+
+	// false skips the children
+	var names []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(ast.IdentExpr); ok {
+			names = append(names, id.Name)
+		}
+		_, isIf := n.(ast.IfStmt)
+		return !isIf
+	})
+	if want := []string{"x", "a", "drop", "x"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("idents outside if %q, want %q", names, want)
+	}
+}
+
+func TestApplyReplace(t *testing.T) {
+	file := parseWalkSrc(t)
+	got := applyTo(t, file, func(c *ast.Cursor) bool {
+		if id, ok := c.Node().(ast.IdentExpr); ok && id.Name == "x" {
+			c.Replace(ast.IdentExpr{Span: id.Span, Name: "y"})
+		}
+		return true
+	}, nil)
+	want := []string{"y", "a", "drop", "y", "y", "y", "y", "a"}
+	if names := idents(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("idents %q, want %q", names, want)
+	}
+}
+
+func TestApplyReplaceElseIf(t *testing.T) {
+	file := parseWalkSrc(t)
+	got := applyTo(t, file, func(c *ast.Cursor) bool {
+		if c.Name() == "ElseIf" {
+			if _, ok := c.Parent().(ast.IfStmt); !ok {
+				t.Errorf("ElseIf parent is %T", c.Parent())
+			}
+			c.Replace(ast.IfStmt{Cond: ast.IdentExpr{Name: "z"}, Then: []ast.Statement{call("g")}})
+		}
+		return true
+	}, nil)
+	elseIf := got.Funcs[0].Body[2].(ast.IfStmt).ElseIf
+	if elseIf == nil {
+		t.Fatal("ElseIf is nil")
+	}
+	if names := idents(*elseIf); !reflect.DeepEqual(names, []string{"z", "g"}) {
+		t.Errorf("ElseIf idents %q, want [z g]", names)
+	}
+
+	// deleting an optional pointer child is a Replace with nil
+	got = applyTo(t, file, func(c *ast.Cursor) bool {
+		if c.Name() == "ElseIf" {
+			c.Replace(nil)
+		}
+		return true
+	}, nil)
+	if got.Funcs[0].Body[2].(ast.IfStmt).ElseIf != nil {
+		t.Error("ElseIf not removed")
+	}
+}
+
+func TestApplyDelete(t *testing.T) {
+	file := parseWalkSrc(t)
+	var visited []string
+	got := applyTo(t, file, func(c *ast.Cursor) bool {
+		if s, ok := c.Node().(ast.ExprStmt); ok {
+			if call, ok := s.Expr.(ast.CallExpr); ok && call.Func.(ast.IdentExpr).Name == "drop" {
+				c.Delete()
+				return true
+			}
+		}
+		if id, ok := c.Node().(ast.IdentExpr); ok {
+			visited = append(visited, id.Name)
+		}
+		return true
+	}, nil)
+	want := []string{"DefineStmt", "IfStmt", "ReturnStmt"}
+	if body := kinds(got.Funcs[0].Body); !reflect.DeepEqual(body, want) {
+		t.Errorf("body %q, want %q", body, want)
+	}
+	// the deleted node's children are skipped, its siblings are not
+	if want := []string{"x", "a", "x", "x", "x", "a"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited %q, want %q", visited, want)
+	}
+}
+
+func TestApplyInsert(t *testing.T) {
+	file := parseWalkSrc(t)
+	calls := 0
+	got := applyTo(t, file, func(c *ast.Cursor) bool {
+		if c.Name() != "Body" && c.Name() != "Then" {
+			return true
+		}
+		calls++
+		switch c.Node().(type) {
+		case ast.ReturnStmt:
+			c.InsertBefore(call("free"))
+		case ast.DefineStmt:
+			c.InsertAfter(call("use"))
+		}
+		return true
+	}, nil)
+
+	body := got.Funcs[0].Body
+	want := []string{"DefineStmt", "ExprStmt", "ExprStmt", "IfStmt", "ExprStmt", "ReturnStmt"}
+	if got := kinds(body); !reflect.DeepEqual(got, want) {
+		t.Fatalf("body %q, want %q", got, want)
+	}
+	if names := idents(body[1]); !reflect.DeepEqual(names, []string{"use"}) {
+		t.Errorf("after the define %q, want [use]", names)
+	}
+	if names := idents(body[4]); !reflect.DeepEqual(names, []string{"free"}) {
+		t.Errorf("before the return %q, want [free]", names)
+	}
+	ifStmt := got.Funcs[0].Body[3].(ast.IfStmt)
+	for _, then := range [][]ast.Statement{ifStmt.Then, ifStmt.ElseIf.Then} {
+		if got := kinds(then); !reflect.DeepEqual(got, []string{"ExprStmt", "ReturnStmt"}) {
+			t.Errorf("then %q, want [ExprStmt ReturnStmt]", got)
+		}
+	}
+
+	// inserted nodes are not visited: 4 statements in Body, 1 in each Then
+	if calls != 6 {
+		t.Errorf("%d statements visited, want 6", calls)
+	}
+}
+
+func TestApplyStop(t *testing.T) {
+	file := parseWalkSrc(t)
+	var post []string
+	got := applyTo(t, file, func(c *ast.Cursor) bool {
+		if id, ok := c.Node().(ast.IdentExpr); ok && id.Name == "a" {
+			c.Replace(ast.IdentExpr{Span: id.Span, Name: "b"})
+		}
+		return true
+	}, func(c *ast.Cursor) bool {
+		id, ok := c.Node().(ast.IdentExpr)
+		if ok {
+			post = append(post, id.Name)
+		}
+		// stop after the first statement's right-hand side
+		return !ok || id.Name != "b"
+	})
+
+	if want := []string{"x", "b"}; !reflect.DeepEqual(post, want) {
+		t.Errorf("post calls %q, want %q", post, want)
+	}
+	// edits made before stopping are kept, later nodes are left as they were
+	want := []string{"x", "b", "drop", "x", "x", "x", "x", "a"}
+	if names := idents(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("idents %q, want %q", names, want)
+	}
+}
@@ -0,0 +1,144 @@
+// Package diag holds the diagnostics reported on Fox source and prints
+// them with the offending line.
+package diag
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"fox/token"
+)
+
+// ================= Diagnostics =================
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Error codes, one per family of problems.
+const (
+	CodeSyntax           = "F0001" // unexpected token
+	CodeUnexpectedEOF    = "F0002" // input ended too early
+	CodeIllegalChar      = "F0003" // character that starts no token
+	CodeBadLiteral       = "F0004" // malformed number, string, rune or comment
+	CodeBadAssign        = "F0005" // left side cannot be assigned to
+	CodePointerToPointer = "F0006" // &&x, **p or **T
+	CodeAssignMismatch   = "F0007" // a, b = x or a, b += x, y
+	CodeBadMethod        = "F0008" // bad receiver type or clashing method
+)
+
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+type Diagnostic struct {
+	ParseError
+	Severity Severity
+	Code     string
+	Span     token.Span
+}
+
+// New makes an error diagnostic for span.
+func New(code string, span token.Span, msg string) Diagnostic {
+	return Diagnostic{
+		ParseError: ParseError{
+			Line:   span.Pos.Line,
+			Column: span.Pos.Column,
+			Msg:    msg,
+		},
+		Severity: SeverityError,
+		Code:     code,
+		Span:     span,
+	}
+}
+
+// SetFile stamps the file name on the diagnostic and its span.
+func (d *Diagnostic) SetFile(name string) {
+	d.File = name
+	d.Span.Pos.File = name
+	d.Span.End.File = name
+}
+
+// List is the diagnostics of a file. As an error it reads as the first
+// one and a count of the rest.
+type List []Diagnostic
+
+func (l List) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0].Error(), len(l)-1)
+}
+
+// Sort orders the list by position in the source.
+func (l List) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		return l[i].Span.Pos.Offset < l[j].Span.Pos.Offset
+	})
+}
+
+// Err returns the list as an error, or nil if it is empty.
+func (l List) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Print writes d as file:line:col: msg, followed by the source line and
+// a caret under the offending column.
+func Print(w io.Writer, d Diagnostic, src string) {
+	fmt.Fprintf(w, "%s:%d:%d: %s\n", d.File, d.Line, d.Column, d.Msg)
+
+	lines := strings.Split(src, "\n")
+	if d.Line < 1 || d.Line > len(lines) {
+		return
+	}
+	text := strings.TrimRight(lines[d.Line-1], "\r")
+
+	// keep tabs so the caret lines up under them
+	var caret strings.Builder
+	col := 1
+	for _, r := range text {
+		if col >= d.Column {
+			break
+		}
+		if r == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+		col++
+	}
+	caret.WriteByte('^')
+
+	// underline the rest of a span that stays on this line
+	if d.Span.End.Line == d.Line {
+		for n := d.Column + 1; n < d.Span.End.Column; n++ {
+			caret.WriteByte('~')
+		}
+	}
+	fmt.Fprintf(w, "%s\n%s\n", text, caret.String())
+}
@@ -0,0 +1,218 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is how many unchanged lines a hunk shows around a change.
+const contextLines = 3
+
+// An edit is one line of a diff: ' ' kept, '-' removed or '+' added.
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the hunks of a unified diff from a to b, without
+// the file header.
+func unifiedDiff(a, b string) string {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		// a hunk runs from a change until more than twice the context of
+		// unchanged lines
+		start := max(i-contextLines, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == ' ' {
+				run++
+			}
+			if run == len(edits) || run-end > 2*contextLines {
+				end = min(end+contextLines, len(edits))
+				break
+			}
+			end = run
+		}
+		writeHunk(&sb, edits, start, end)
+		i = end
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []edit, start, end int) {
+	// line numbers of the hunk start on both sides
+	aLine, bLine := 1, 1
+	for _, e := range edits[:start] {
+		if e.op != '+' {
+			aLine++
+		}
+		if e.op != '-' {
+			bLine++
+		}
+	}
+	aCount, bCount := 0, 0
+	for _, e := range edits[start:end] {
+		if e.op != '+' {
+			aCount++
+		}
+		if e.op != '-' {
+			bCount++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aLine, aCount, bLine, bCount)
+	for _, e := range edits[start:end] {
+		sb.WriteByte(e.op)
+		sb.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// splitLines splits s after each newline; a last line without one is
+// kept as it is.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines turns a into b with the fewest removed and added lines. It is
+// Myers' diff in linear space: the common prefix and suffix are cut off
+// and the rest is split at a middle snake, found by searching from both
+// ends at once.
+func diffLines(a, b []string) []edit {
+	d := &differ{a: a, b: b}
+	d.diff(0, len(a), 0, len(b))
+	return d.edits
+}
+
+type differ struct {
+	a, b  []string
+	edits []edit
+}
+
+// diff appends the edits turning a[aLo:aHi] into b[bLo:bHi].
+func (d *differ) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, edit{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	if x, y, ok := d.middle(aLo, aHi, bLo, bHi); ok {
+		d.diff(aLo, x, bLo, y)
+		d.diff(x, aHi, y, bHi)
+	} else {
+		for _, line := range d.a[aLo:aHi] {
+			d.edits = append(d.edits, edit{'-', line})
+		}
+		for _, line := range d.b[bLo:bHi] {
+			d.edits = append(d.edits, edit{'+', line})
+		}
+	}
+
+	for _, line := range d.a[aHi : aHi+suffix] {
+		d.edits = append(d.edits, edit{' ', line})
+	}
+}
+
+// middle finds where the shortest edit script of a[aLo:aHi] into
+// b[bLo:bHi] crosses from the forward search into the backward one. It
+// fails when the ranges have no line in common, or one of them is empty.
+func (d *differ) middle(aLo, aHi, bLo, bHi int) (x, y int, ok bool) {
+	n, m := aHi-aLo, bHi-bLo
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// fwd[offset+k] is the furthest x reached on diagonal k = x-y from the
+	// start, bwd the same from the end; -1 where not reached yet
+	fwd := make([]int, 2*maxD+2)
+	bwd := make([]int, 2*maxD+2)
+	for i := range fwd {
+		fwd[i], bwd[i] = -1, -1
+	}
+	fwd[offset+1], bwd[offset+1] = 0, 0
+	delta := n - m
+	odd := delta%2 != 0
+
+	// diagonals running off the grid narrow the k ranges
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for dist := 0; dist < maxD; dist++ {
+		for k := -dist + fStart; k <= dist-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -dist || k != dist && fwd[i-1] < fwd[i+1] {
+				x = fwd[i+1]
+			} else {
+				x = fwd[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			fwd[i] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if j := offset + delta - k; j >= 0 && j < len(bwd) && bwd[j] != -1 && x >= n-bwd[j] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for k := -dist + bStart; k <= dist-bEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -dist || k != dist && bwd[i-1] < bwd[i+1] {
+				x = bwd[i+1]
+			} else {
+				x = bwd[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			bwd[i] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if j := offset + delta - k; j >= 0 && j < len(fwd) && fwd[j] != -1 {
+					fx := fwd[j]
+					if fx >= n-x {
+						return aLo + fx, bLo + fx - (j - offset), true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
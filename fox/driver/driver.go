@@ -0,0 +1,71 @@
+// Package driver is the fox command: it parses the files named on the
+// command line, writes their ASTs as JSON and reports their errors.
+// fox fmt formats files instead.
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"fox/ast"
+	"fox/diag"
+	"fox/parser"
+)
+
+// Run runs the fox command with args, the arguments after the program
+// name, and returns its exit status. ASTs go to stdout, diagnostics to
+// stderr.
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "fmt" {
+		return runFmt(args[1:], stdout, stderr)
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: fox file.fox...")
+		fmt.Fprintln(stderr, "       fox fmt [-w] [-d] file.fox...")
+		return 2
+	}
+
+	status := 0
+	for _, name := range args {
+		if !dumpFile(name, stdout, stderr) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// dumpFile parses the named file and dumps its AST, even with errors as
+// bad parts are marked Bad*. It reports whether the file was clean.
+func dumpFile(name string, stdout, stderr io.Writer) bool {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
+	}
+
+	file, err := parser.ParseFile(name, src)
+	if perr := ast.Fprint(stdout, file); perr != nil {
+		fmt.Fprintln(stderr, perr)
+		return false
+	}
+	return reportErrors(stderr, err, src)
+}
+
+// reportErrors prints err, with the source line of each diagnostic, and
+// reports whether there was none.
+func reportErrors(w io.Writer, err error, src []byte) bool {
+	if err == nil {
+		return true
+	}
+	var list diag.List
+	if !errors.As(err, &list) {
+		fmt.Fprintln(w, err)
+		return false
+	}
+	for _, d := range list {
+		diag.Print(w, d, string(src))
+	}
+	return false
+}
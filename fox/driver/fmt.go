@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"fox/format"
+)
+
+// runFmt runs fox fmt: each file is formatted to stdout, or with -w
+// written back when it changed; -d prints a diff instead.
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write the result to the file instead of stdout")
+	diff := flags.Bool("d", false, "print a diff of the changes instead of the result")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: fox fmt [-w] [-d] file.fox...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	status := 0
+	for _, name := range flags.Args() {
+		if !formatFile(name, *write, *diff, stdout, stderr) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// formatFile formats the named file and reports whether that worked.
+// Files with errors are left as they are.
+func formatFile(name string, write, diff bool, stdout, stderr io.Writer) bool {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
+	}
+	out, err := format.Source(name, src)
+	if err != nil {
+		return reportErrors(stderr, err, src)
+	}
+
+	if diff && !bytes.Equal(src, out) {
+		fmt.Fprintf(stdout, "--- %s.orig\n+++ %s\n", name, name)
+		io.WriteString(stdout, unifiedDiff(string(src), string(out)))
+	}
+	if write && !bytes.Equal(src, out) {
+		info, err := os.Stat(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return false
+		}
+		if err := os.WriteFile(name, out, info.Mode().Perm()); err != nil {
+			fmt.Fprintln(stderr, err)
+			return false
+		}
+	}
+	if !write && !diff {
+		stdout.Write(out)
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package driver_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fox/driver"
+)
+
+const (
+	messy = "package p\n\nfunc f() {\n    x  = 1\n}\n"
+	tidy  = "package p\n\nfunc f() {\n\tx = 1\n}\n"
+)
+
+// run runs the fox command and returns its status, stdout and stderr.
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := driver.Run(args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, src string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "p.fox")
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFmt(t *testing.T) {
+	name := writeFile(t, messy)
+	status, stdout, stderr := run("fmt", name)
+	if status != 0 || stderr != "" {
+		t.Fatalf("status %d, stderr %q", status, stderr)
+	}
+	if stdout != tidy {
+		t.Errorf("stdout %q, want %q", stdout, tidy)
+	}
+	if got := readFile(t, name); got != messy {
+		t.Errorf("file changed to %q without -w", got)
+	}
+}
+
+func TestFmtWrite(t *testing.T) {
+	name := writeFile(t, messy)
+	status, stdout, stderr := run("fmt", "-w", name)
+	if status != 0 || stdout != "" || stderr != "" {
+		t.Fatalf("status %d, stdout %q, stderr %q", status, stdout, stderr)
+	}
+	if got := readFile(t, name); got != tidy {
+		t.Errorf("file is %q, want %q", got, tidy)
+	}
+}
+
+func TestFmtDiff(t *testing.T) {
+	name := writeFile(t, messy)
+	status, stdout, stderr := run("fmt", "-d", name)
+	if status != 0 || stderr != "" {
+		t.Fatalf("status %d, stderr %q", status, stderr)
+	}
+	want := "--- " + name + ".orig\n+++ " + name + "\n" +
+		"@@ -1,5 +1,5 @@\n" +
+		" package p\n \n func f() {\n-    x  = 1\n+\tx = 1\n }\n"
+	if stdout != want {
+		t.Errorf("diff\n%s\nwant\n%s", stdout, want)
+	}
+	if got := readFile(t, name); got != messy {
+		t.Errorf("file changed to %q by -d", got)
+	}
+
+	// nothing to print for a formatted file
+	name = writeFile(t, tidy)
+	if status, stdout, _ := run("fmt", "-d", name); status != 0 || stdout != "" {
+		t.Errorf("status %d, diff %q for a formatted file", status, stdout)
+	}
+}
+
+func TestFmtErrors(t *testing.T) {
+	src := "package p\n\nfunc f() {\n    x :=\n}\n"
+	name := writeFile(t, src)
+	status, stdout, stderr := run("fmt", "-w", name)
+	if status != 1 || stdout != "" || !strings.Contains(stderr, "p.fox:") {
+		t.Errorf("status %d, stdout %q, stderr %q", status, stdout, stderr)
+	}
+	if got := readFile(t, name); got != src {
+		t.Errorf("file with errors rewritten to %q", got)
+	}
+
+	if status, _, _ := run("fmt"); status != 2 {
+		t.Errorf("status %d without files, want 2", status)
+	}
+}
@@ -0,0 +1,372 @@
+// Package format prints Fox ASTs back to source in the canonical layout:
+// tabs for indentation, one statement per line, operator spacing by
+// precedence, aligned columns in struct types and declaration groups, and
+// the comments of the original source kept in place.
+package format
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"fox/ast"
+	"fox/lexer"
+	"fox/parser"
+	"fox/token"
+)
+
+// Source formats the source of the named file. Files with errors are not
+// formatted; the error is then the parser's diag.List.
+//
+// The result is checked to parse and to format to itself, so a layout
+// that would change on a second run is reported instead of written out.
+func Source(name string, src []byte) ([]byte, error) {
+	out, err := format(name, src)
+	if err != nil {
+		return nil, err
+	}
+	again, err := format(name, out)
+	if err != nil {
+		return nil, errors.New(name + ": formatted source does not parse: " + err.Error())
+	}
+	if !bytes.Equal(out, again) {
+		return nil, errors.New(name + ": formatting is not stable")
+	}
+	return out, nil
+}
+
+func format(name string, src []byte) ([]byte, error) {
+	file, err := parser.ParseFile(name, src)
+	if err != nil {
+		return nil, err
+	}
+	return Node(file, src), nil
+}
+
+// Node prints file, parsed from src. The source supplies what the AST does
+// not keep: the comments, the brace positions and the spelling of literals.
+func Node(file *ast.File, src []byte) []byte {
+	tokens, _ := lexer.Tokenize(string(src))
+	p := &printer{out: &bytes.Buffer{}, src: src, tokens: tokens}
+	for _, tok := range tokens {
+		p.comments = append(p.comments, tok.Leading...)
+		p.comments = append(p.comments, tok.Trailing...)
+	}
+	sort.Slice(p.comments, func(i, j int) bool {
+		return p.comments[i].Offset < p.comments[j].Offset
+	})
+	p.done = make([]bool, len(p.comments))
+	p.own = make([]bool, len(p.comments))
+	for i, c := range p.comments {
+		start := bytes.LastIndexByte(src[:c.Offset], '\n') + 1
+		end := c.Offset + len(c.Text)
+		if n := bytes.IndexByte(src[end:], '\n'); n >= 0 {
+			end += n
+		} else {
+			end = len(src)
+		}
+		p.own[i] = len(bytes.TrimSpace(src[start:c.Offset])) == 0 &&
+			len(bytes.TrimSpace(src[c.Offset+len(c.Text):end])) == 0
+	}
+
+	p.file(file)
+	return p.out.Bytes()
+}
+
+// ================= Printer =================
+
+type printer struct {
+	out    *bytes.Buffer
+	indent int
+	src    []byte
+	tokens []token.Token
+
+	comments []token.Comment
+	done     []bool // comments already printed
+	own      []bool // comments alone on their lines
+	first    int    // all comments before it are done
+
+	// source line of the last thing printed, for keeping blank lines;
+	// 0 right after an opening brace, where blank lines are dropped
+	lastLine int
+	// source line of the '(' of the innermost call printed
+	callLine int
+}
+
+func (p *printer) write(s ...string) {
+	for _, s := range s {
+		p.out.WriteString(s)
+	}
+}
+
+func (p *printer) newline() {
+	p.out.WriteByte('\n')
+	p.out.WriteString(strings.Repeat("\t", p.indent))
+}
+
+// source returns the text of span as written.
+func (p *printer) source(span token.Span) string {
+	return string(p.src[span.Pos.Offset:span.End.Offset])
+}
+
+// capture returns what f prints instead of printing it.
+func (p *printer) capture(f func()) string {
+	saved := p.out
+	p.out = &bytes.Buffer{}
+	f()
+	s := p.out.String()
+	p.out = saved
+	return s
+}
+
+// ================= Comments and lines =================
+
+// startLine begins a new line for something at pos. The comments on the
+// lines before go first, each on its own line, and a blank line of the
+// source in front of either is kept. blank forces one before the first of
+// them. Comments in front of pos on its line stay there.
+func (p *printer) startLine(pos token.Position, blank bool) {
+	blank = p.flush(pos.Offset, pos.Line, blank)
+	p.lineBreak(pos.Line, blank)
+	p.inline(pos.Offset)
+}
+
+// flush prints the comments before offset that start before line on lines
+// of their own and reports whether a forced blank line is still due.
+func (p *printer) flush(offset, line int, blank bool) bool {
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Offset < offset; i++ {
+		c := p.comments[i]
+		if p.done[i] || c.Line >= line {
+			continue
+		}
+		p.done[i] = true
+		p.lineBreak(c.Line, blank)
+		blank = false
+		p.write(c.Text)
+		p.lastLine = c.Line + strings.Count(c.Text, "\n")
+	}
+	return blank
+}
+
+// inline prints the comments before offset where the line has got to, as
+// in f(a, /* b */ c). A // comment ends the line, which goes on one tab
+// further in. Comments alone on their lines wait for flush or endLine.
+func (p *printer) inline(offset int) {
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Offset < offset; i++ {
+		if p.done[i] || p.own[i] {
+			continue
+		}
+		p.done[i] = true
+		if b := p.out.Bytes(); len(b) > 0 && !strings.ContainsRune(" \t([{", rune(b[len(b)-1])) {
+			p.write(" ")
+		}
+		text := p.comments[i].Text
+		p.write(text)
+		if strings.HasPrefix(text, "//") {
+			p.out.WriteByte('\n')
+			p.write(strings.Repeat("\t", p.indent+1))
+		} else {
+			p.write(" ")
+		}
+	}
+}
+
+// trail prints the comments before offset, a comma or operator, after
+// what they follow, as in f(a /* a */, b). A // comment ends the line
+// like in inline.
+func (p *printer) trail(offset int) {
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Offset < offset; i++ {
+		if p.done[i] || p.own[i] {
+			continue
+		}
+		p.done[i] = true
+		text := p.comments[i].Text
+		p.write(" ", text)
+		if strings.HasPrefix(text, "//") {
+			p.out.WriteByte('\n')
+			p.write(strings.Repeat("\t", p.indent+1))
+		}
+	}
+}
+
+// lineBreak ends the current line, adding a blank one when forced or
+// when the source had one before line.
+func (p *printer) lineBreak(line int, blank bool) {
+	if p.out.Len() > 0 {
+		if blank || p.lastLine > 0 && line > p.lastLine+1 {
+			p.out.WriteByte('\n')
+		}
+		p.newline()
+	} else {
+		p.write(strings.Repeat("\t", p.indent))
+	}
+}
+
+// endLine finishes a line whose source ended at end. Comments left inside
+// that source and the ones after it on its last line are appended.
+func (p *printer) endLine(end token.Position) {
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Offset < end.Offset; i++ {
+		if !p.done[i] {
+			p.done[i] = true
+			p.write(" ", p.comments[i].Text)
+		}
+	}
+	for _, text := range p.trailing(end) {
+		p.write(" ", text)
+	}
+	p.lastLine = end.Line
+}
+
+// trailing takes the comments after end on its line.
+func (p *printer) trailing(end token.Position) []string {
+	var texts []string
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Line <= end.Line; i++ {
+		if c := p.comments[i]; !p.done[i] && c.Line == end.Line && c.Offset >= end.Offset {
+			p.done[i] = true
+			texts = append(texts, c.Text)
+		}
+	}
+	return texts
+}
+
+// hasComments reports whether any comment is left between the offsets.
+func (p *printer) hasComments(from, to int) bool {
+	for i := p.pending(); i < len(p.comments) && p.comments[i].Offset < to; i++ {
+		if !p.done[i] && p.comments[i].Offset >= from {
+			return true
+		}
+	}
+	return false
+}
+
+// pending is the index of the first comment not printed yet; the ones
+// before it need no looking at.
+func (p *printer) pending() int {
+	for p.first < len(p.comments) && p.done[p.first] {
+		p.first++
+	}
+	return p.first
+}
+
+// closeBrace prints the comments left before close, a closing brace or
+// paren, then text for it on a line of its own at the outer indent.
+func (p *printer) closeBrace(close token.Token, text string) {
+	p.flush(close.Offset, close.Line+1, false)
+	p.indent--
+	p.newline()
+	p.write(text)
+	p.lastLine = close.Line
+}
+
+// ================= Token lookup =================
+
+// next returns the first token of type typ at or after offset.
+func (p *printer) next(typ string, offset int) (token.Token, int) {
+	i := sort.Search(len(p.tokens), func(i int) bool { return p.tokens[i].Offset >= offset })
+	for ; i < len(p.tokens); i++ {
+		if p.tokens[i].Type == typ {
+			return p.tokens[i], i
+		}
+	}
+	return p.tokens[len(p.tokens)-1], len(p.tokens) - 1
+}
+
+// braces finds the first open token, such as '{', at or after offset and
+// its matching close.
+func (p *printer) braces(open, close string, offset int) (token.Token, token.Token) {
+	first, i := p.next(open, offset)
+	depth := 0
+	for ; i < len(p.tokens); i++ {
+		switch p.tokens[i].Type {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return first, p.tokens[i]
+			}
+		}
+	}
+	return first, p.tokens[len(p.tokens)-1]
+}
+
+// ================= Aligned columns =================
+
+// A row is one line of a struct type or declaration group, split into
+// cells whose columns line up across a section.
+type row struct {
+	span     token.Span
+	lead     string // comments in front of the row on its line
+	cells    []string
+	trailing []string
+}
+
+// lead takes the comments in front of a row starting at pos, which are
+// then left out of its alignment.
+func (p *printer) lead(pos token.Position) string {
+	return p.capture(func() { p.inline(pos.Offset) })
+}
+
+// rows prints lines aligned in columns like gofmt. A section of aligned
+// lines ends at a blank line, an own-line comment or a cell that spans
+// lines.
+func (p *printer) rows(rows []row) {
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && !p.breaksSection(rows[end-1], rows[end]) {
+			end++
+		}
+		widths := columnWidths(rows[start:end])
+		for _, r := range rows[start:end] {
+			p.startLine(r.span.Pos, false)
+			p.write(r.lead)
+			var line strings.Builder
+			for i, cell := range r.cells {
+				if cell == "" {
+					continue
+				}
+				line.WriteString(cell)
+				if i < len(r.cells)-1 || len(r.trailing) > 0 {
+					line.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))+1))
+				}
+			}
+			line.WriteString(strings.Join(r.trailing, " "))
+			p.write(strings.TrimRight(line.String(), " "))
+			p.lastLine = r.span.End.Line
+		}
+		start = end
+	}
+}
+
+func (p *printer) breaksSection(prev, next row) bool {
+	if next.span.Pos.Line > prev.span.End.Line+1 || p.hasComments(prev.span.End.Offset, next.span.Pos.Offset) {
+		return true
+	}
+	for _, r := range []row{prev, next} {
+		for _, cell := range r.cells {
+			if strings.Contains(cell, "\n") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// columnWidths is the widest cell of each column but the last. Empty
+// cells are left out, along with their padding.
+func columnWidths(rows []row) []int {
+	n := 0
+	for _, r := range rows {
+		n = max(n, len(r.cells))
+	}
+	widths := make([]int, n)
+	for _, r := range rows {
+		for i, cell := range r.cells {
+			if i < len(r.cells)-1 || len(r.trailing) > 0 {
+				widths[i] = max(widths[i], len([]rune(cell)))
+			}
+		}
+	}
+	return widths
+}
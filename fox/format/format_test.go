@@ -0,0 +1,116 @@
+package format_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"fox/format"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestGolden(t *testing.T) {
+	src, err := os.ReadFile("../../test.fox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := format.Source("test.fox", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const golden = "testdata/test.fox.golden"
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("test.fox formatted to\n%s\nwant\n%s", got, want)
+	}
+	checkIdempotent(t, "test.fox", got)
+}
+
+// checkIdempotent fails unless formatted src formats to itself.
+func checkIdempotent(t *testing.T, name string, src []byte) {
+	t.Helper()
+	again, err := format.Source(name, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, src) {
+		t.Errorf("%s: formatting again gave\n%s\nwant\n%s", name, again, src)
+	}
+}
+
+var tests = []struct {
+	name, src, want string
+}{
+	{
+		"comments",
+		"package p\n\n// T is a type.\ntype T struct {\n    a int // a\n    // last\n}\n\nfunc f(a int /* a */, b int) {\n    x := g(a, /* inline */ b)\n    y := h(a, // first\n        b)\n    z := T{\n        /* k */ k: 1,\n    }\n    // before close\n}\n// end\n",
+		"package p\n\n// T is a type.\ntype T struct {\n\ta int // a\n\t// last\n}\n\nfunc f(a int /* a */, b int) {\n\tx := g(a, /* inline */ b)\n\ty := h(a, // first\n\t\tb)\n\tz := T{\n\t\t/* k */ k: 1,\n\t}\n\t// before close\n}\n// end\n",
+	},
+	{
+		"trailing comments",
+		"package p\n\nfunc f(a int /* a */, b int) (q int /* q */, err error) {\n    x := a /* mid */ + b\n    u := T{a: 1, /* c */\n    }\n    v := g(a /* a */, b /* b */)\nL: // c\n    for {\n        break L\n    }\n}\n",
+		"package p\n\nfunc f(a int /* a */, b int) (q int /* q */, err error) {\n\tx := a /* mid */ + b\n\tu := T{\n\t\ta: 1, /* c */\n\t}\n\tv := g(a /* a */, b /* b */)\nL: // c\n\tfor {\n\t\tbreak L\n\t}\n}\n",
+	},
+	{
+		"multi-line calls",
+		"package p\n\nfunc f() {\n    x := g(a,\n        b, c)\n    y := g(\n        a, // a\n\n        // own\n        b,\n    )\n    z := g(a, k(\n        b), c)\n    w := g(\n        a)\n}\n",
+		"package p\n\nfunc f() {\n\tx := g(a,\n\t\tb, c)\n\ty := g(\n\t\ta, // a\n\n\t\t// own\n\t\tb,\n\t)\n\tz := g(a, k(\n\t\tb), c)\n\tw := g(\n\t\ta)\n}\n",
+	},
+	{
+		"grouped decls",
+		"package p\n\ntype (\n    Age int\n    Name string\n    Info = Name\n)\n\nvar (\n    d int\n    e, f = load()\n    g *T\n)\n\nconst (\n    A = iota\n    B\n    D, E = iota, iota*2\n)\n\ntype S struct {\n    a, b int\n    name string `json:\"name\"`\n    *Base\n}\n",
+		"package p\n\ntype (\n\tAge  int\n\tName string\n\tInfo = Name\n)\n\nvar (\n\td    int\n\te, f = load()\n\tg    *T\n)\n\nconst (\n\tA    = iota\n\tB\n\tD, E = iota, iota * 2\n)\n\ntype S struct {\n\ta, b int\n\tname string `json:\"name\"`\n\t*Base\n}\n",
+	},
+	{
+		"switch and labels",
+		"package p\n\nfunc f(x int) int {\n    switch x {\n    case 1, 2:\n        y = 1\n        fallthrough\n    case 3:\n    default:\n        y = 2\n    }\n    switch {\n    case x>1&&x<3:\n        return 1\n    }\nouter:\n    for i := 0; i < 3; i++ {\n        for j := range xs {\n            if j == 1 {\n                continue outer\n            } else if j == 2 {\n                break outer\n            } else {\n                j++\n            }\n        }\n    }\n    return x\n}\n",
+		"package p\n\nfunc f(x int) int {\n\tswitch x {\n\tcase 1, 2:\n\t\ty = 1\n\t\tfallthrough\n\tcase 3:\n\tdefault:\n\t\ty = 2\n\t}\n\tswitch {\n\tcase x > 1 && x < 3:\n\t\treturn 1\n\t}\nouter:\n\tfor i := 0; i < 3; i++ {\n\t\tfor j := range xs {\n\t\t\tif j == 1 {\n\t\t\t\tcontinue outer\n\t\t\t} else if j == 2 {\n\t\t\t\tbreak outer\n\t\t\t} else {\n\t\t\t\tj++\n\t\t\t}\n\t\t}\n\t}\n\treturn x\n}\n",
+	},
+	{
+		"composite literals",
+		"package p\n\nfunc f() {\n    u := User{name:n,age:3}\n    o := Outer{in: Inner{x: 1}, pts: Pts{{1, 2}, {3, 4}}}\n    v := &User{\n        name: n, // the name\n        age: 3}\n}\n",
+		"package p\n\nfunc f() {\n\tu := User{name: n, age: 3}\n\to := Outer{in: Inner{x: 1}, pts: Pts{{1, 2}, {3, 4}}}\n\tv := &User{\n\t\tname: n, // the name\n\t\tage:  3,\n\t}\n}\n",
+	},
+	{
+		"signatures",
+		"package p\n\ntype S struct {\n    f func()\n    cb func() \"tag\"\n    g func(a, b int) (q, r int)\n}\n\nfunc f(a, b int, c string) (q, r int, err error) {\n    return\n}\n\nfunc g(int, string) (Data, int) {\n}\n",
+		"package p\n\ntype S struct {\n\tf  func()\n\tcb func() \"tag\"\n\tg  func(a, b int) (q, r int)\n}\n\nfunc f(a, b int, c string) (q, r int, err error) {\n\treturn\n}\n\nfunc g(int, string) Data, int {\n}\n",
+	},
+	{
+		"operators and literals",
+		"package p\n\nfunc f() {\n    z := a * b+c - d/e\n    w := xs[i + 1 : j]+f(a, -b)\n    ok := !p&&q||r\n    s := `raw\nstring`\n    r := '\\xff'\n    e := \"\\u00e9\"\n}\n",
+		"package p\n\nfunc f() {\n\tz := a*b + c - d/e\n\tw := xs[i+1:j] + f(a, -b)\n\tok := !p && q || r\n\ts := `raw\nstring`\n\tr := '\\xff'\n\te := \"\\u00e9\"\n}\n",
+	},
+}
+
+func TestSource(t *testing.T) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := format.Source(tt.name+".fox", []byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			checkIdempotent(t, tt.name+".fox", got)
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	if _, err := format.Source("bad.fox", []byte("package p\n\nfunc f() {\n\tx :=\n}\n")); err == nil {
+		t.Error("source with errors formatted")
+	}
+}
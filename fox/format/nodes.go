@@ -0,0 +1,771 @@
+package format
+
+import (
+	"sort"
+	"strings"
+
+	"fox/ast"
+	"fox/token"
+)
+
+// ================= Declarations =================
+
+func (p *printer) file(f *ast.File) {
+	if f.PackageName != "" {
+		pkg, i := p.next(token.Keyword.Package, 0)
+		p.startLine(pkg.Pos(), false)
+		p.write("package ", f.PackageName)
+		p.endLine(p.tokens[i+1].End)
+	}
+	p.imports(f)
+
+	type decl struct {
+		span  token.Span
+		print func()
+	}
+	var decls []decl
+	for _, d := range f.Types {
+		decls = append(decls, decl{d.Span, func() { p.typeDecl(d) }})
+	}
+	for _, d := range f.Vars {
+		decls = append(decls, decl{d.Span, func() { p.valueDecl("var", d.Grouped, d.Span, d.Specs) }})
+	}
+	for _, d := range f.Consts {
+		decls = append(decls, decl{d.Span, func() { p.valueDecl("const", d.Grouped, d.Span, d.Specs) }})
+	}
+	for _, d := range f.Funcs {
+		decls = append(decls, decl{d.Span, func() { p.funcDecl(d) }})
+	}
+	sort.Slice(decls, func(i, j int) bool {
+		return decls[i].span.Pos.Offset < decls[j].span.Pos.Offset
+	})
+
+	for _, d := range decls {
+		p.startLine(d.span.Pos, false)
+		d.print()
+		p.endLine(d.span.End)
+	}
+
+	// comments after the last declaration
+	end := int(^uint(0) >> 1)
+	p.startLine(token.Position{Offset: end, Line: end}, false)
+	p.out.Truncate(len(strings.TrimRight(p.out.String(), " \t\n")))
+	p.write("\n")
+}
+
+// import ( a; b ) with the names from the AST and their positions from
+// the tokens
+func (p *printer) imports(f *ast.File) {
+	imp, i := p.next(token.Keyword.Import, 0)
+	if imp.Type != token.Keyword.Import {
+		return
+	}
+	p.startLine(imp.Pos(), true)
+	_, close := p.braces(token.Delimiter.LParen, token.Delimiter.RParen, imp.Offset)
+	if len(f.Imports) == 0 && !p.hasComments(imp.Offset, close.Offset) {
+		p.write("import ()")
+		p.endLine(close.End)
+		return
+	}
+
+	p.write("import (")
+	p.indent++
+	p.lastLine = 0
+	names := f.Imports
+	for ; i < len(p.tokens) && p.tokens[i].Offset < close.Offset && len(names) > 0; i++ {
+		if tok := p.tokens[i]; tok.Type == token.Ident.Ident && tok.Value == names[0] {
+			p.startLine(tok.Pos(), false)
+			p.write(tok.Value)
+			p.endLine(tok.End)
+			names = names[1:]
+		}
+	}
+	p.closeBrace(close, ")")
+	p.endLine(close.End)
+}
+
+func (p *printer) typeDecl(d ast.TypeDecl) {
+	if !d.Grouped {
+		spec := d.Specs[0]
+		p.write("type ", spec.Name, " ")
+		if spec.Alias {
+			p.write("= ")
+		}
+		p.typ(spec.Type)
+		return
+	}
+	p.group("type", d.Span, len(d.Specs), func(i int) row {
+		spec := d.Specs[i]
+		lead := p.lead(spec.Pos)
+		typ := p.capture(func() { p.typ(spec.Type) })
+		if spec.Alias {
+			typ = "= " + typ
+		}
+		return row{span: spec.Span, lead: lead, cells: []string{spec.Name, typ}}
+	})
+}
+
+// var or const declaration, also as a statement
+func (p *printer) valueDecl(keyword string, grouped bool, span token.Span, specs []ast.ValueSpec) {
+	if !grouped {
+		var cells []string
+		for _, cell := range p.valueSpec(specs[0]) {
+			if cell != "" {
+				cells = append(cells, cell)
+			}
+		}
+		p.write(keyword, " ", strings.Join(cells, " "))
+		return
+	}
+	p.group(keyword, span, len(specs), func(i int) row {
+		lead := p.lead(specs[i].Pos)
+		return row{span: specs[i].Span, lead: lead, cells: p.valueSpec(specs[i])}
+	})
+}
+
+// valueSpec splits a, b T = x, y into its names, type and values. A
+// const line that repeats the one before shows just its names.
+func (p *printer) valueSpec(spec ast.ValueSpec) []string {
+	cells := []string{strings.Join(spec.Names, ", ")}
+	if spec.Implicit {
+		return cells
+	}
+	typ, values := "", ""
+	if spec.Type != nil {
+		typ = p.capture(func() { p.typ(spec.Type) })
+	}
+	if len(spec.Values) > 0 {
+		values = "= " + p.capture(func() { p.exprList(spec.Values) })
+	}
+	return append(cells, typ, values)
+}
+
+// group prints keyword ( ... ) with n aligned lines made by line.
+func (p *printer) group(keyword string, span token.Span, n int, line func(i int) row) {
+	_, close := p.braces(token.Delimiter.LParen, token.Delimiter.RParen, span.Pos.Offset)
+	if n == 0 && !p.hasComments(span.Pos.Offset, close.Offset) {
+		p.write(keyword, " ()")
+		return
+	}
+	p.write(keyword, " (")
+	p.indent++
+	p.lastLine = 0
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i] = line(i)
+		rows[i].trailing = p.trailing(rows[i].span.End)
+	}
+	p.rows(rows)
+	p.closeBrace(close, ")")
+}
+
+func (p *printer) funcDecl(f ast.FuncDecl) {
+	p.write("func ")
+	if f.Recv != nil {
+		p.write("(")
+		if f.Recv.Name != "" {
+			p.write(f.Recv.Name, " ")
+		}
+		p.typ(f.Recv.Type)
+		p.write(") ")
+	}
+	p.write(f.Name, "(")
+	p.sigList(sigs(f.Params))
+	p.write(")")
+
+	// Fox lists unnamed results bare: func f() Data, int
+	_, rparen := p.braces(token.Delimiter.LParen, token.Delimiter.RParen, f.NamePos.Offset)
+	bodyFrom := rparen.End.Offset
+	if n := len(f.Returns); n > 0 {
+		p.write(" ")
+		p.results(f.Returns, true)
+		bodyFrom = f.Returns[n-1].End.Offset
+	}
+	p.write(" ")
+	p.block(f.Body, bodyFrom)
+}
+
+// results prints a result list; bare allows Data, int without parens,
+// which only a function declaration does.
+func (p *printer) results(list []ast.ReturnSig, bare bool) {
+	named := list[0].Name != ""
+	if !named && (len(list) == 1 || bare) {
+		for i, r := range list {
+			if i > 0 {
+				p.comma(list[i-1].End.Offset)
+			}
+			p.typ(r.Type)
+		}
+		return
+	}
+	p.write("(")
+	p.sigList(list)
+	p.write(")")
+}
+
+// sigList prints a parameter or result list; names that shared a type in
+// the source, a, b int, share it again.
+func (p *printer) sigList(list []ast.ReturnSig) {
+	for i, r := range list {
+		if i > 0 {
+			p.comma(list[i-1].End.Offset)
+		}
+		p.inline(r.Pos.Offset)
+		if r.Name == "" {
+			p.typ(r.Type)
+			continue
+		}
+		p.write(r.Name)
+		if i+1 < len(list) && list[i+1].Name != "" && sameType(r, list[i+1]) {
+			continue
+		}
+		p.write(" ")
+		p.typ(r.Type)
+	}
+}
+
+// sameType reports whether a and b got their type from the same source.
+func sameType(a, b ast.ReturnSig) bool {
+	span := a.Type.NodeSpan()
+	return span.End.Offset > 0 && span == b.Type.NodeSpan()
+}
+
+// sigs makes parameters into the entries sigList prints.
+func sigs(params []ast.ParamDecl) []ast.ReturnSig {
+	list := make([]ast.ReturnSig, len(params))
+	for i, param := range params {
+		list[i] = ast.ReturnSig{Span: param.Span, Name: param.Name, Type: param.Type}
+	}
+	return list
+}
+
+// ================= Statements =================
+
+// block prints { stmts }, whose '{' is the first one from offset on,
+// and returns its '}'.
+func (p *printer) block(stmts []ast.Statement, from int) token.Token {
+	open, close := p.braces(token.Delimiter.LBrace, token.Delimiter.RBrace, from)
+	p.inline(open.Offset)
+	if len(stmts) == 0 && !p.hasComments(open.Offset, close.Offset) {
+		p.emptyBraces(open, close, "{}")
+		return close
+	}
+	p.write("{")
+	p.endLine(open.End)
+	p.indent++
+	p.lastLine = 0
+	for _, s := range stmts {
+		p.stmtLine(s)
+	}
+	p.closeBrace(close, "}")
+	return close
+}
+
+// emptyBraces prints text, such as {}, for an empty pair of braces; like
+// in the source the closing one may go on a line of its own.
+func (p *printer) emptyBraces(open, close token.Token, text string) {
+	if open.Line == close.Line {
+		p.write(text)
+		return
+	}
+	p.write(strings.TrimSuffix(text, "}"))
+	p.newline()
+	p.write("}")
+	p.lastLine = close.Line
+}
+
+// stmtLine prints s on a line of its own; a label goes on the line before
+// it, one tab to the left.
+func (p *printer) stmtLine(s ast.Statement) {
+	span := s.NodeSpan()
+	if l, ok := s.(ast.LabeledStmt); ok {
+		p.startLine(span.Pos, false)
+		if p.indent > 0 {
+			p.out.Truncate(p.out.Len() - 1)
+		}
+		p.write(l.Label, ":")
+		p.lastLine = span.Pos.Line
+		// comments after the colon stay on the label's line, unless the
+		// statement there takes them along
+		if colon, _ := p.next(token.Delimiter.Colon, span.Pos.Offset); l.Stmt.NodeSpan().Pos.Line > colon.Line {
+			p.endLine(colon.End)
+		}
+		p.stmtLine(l.Stmt)
+		return
+	}
+	p.startLine(span.Pos, false)
+	p.stmt(s)
+	p.endLine(span.End)
+}
+
+func (p *printer) stmt(s ast.Statement) {
+	switch s := s.(type) {
+	case ast.ExprStmt:
+		p.expr(s.Expr)
+	case ast.IncDecStmt:
+		p.expr(s.X)
+		p.write(s.Op)
+	case ast.AssignStmt:
+		p.exprList(s.Lhs)
+		p.write(" ", s.Op, " ")
+		p.exprList(s.Rhs)
+	case ast.DefineStmt:
+		p.exprList(s.Lhs)
+		p.write(" := ")
+		p.exprList(s.Rhs)
+	case ast.ReturnStmt:
+		p.write("return")
+		if len(s.RetValues) > 0 {
+			p.write(" ")
+			p.exprList(s.RetValues)
+		}
+	case ast.BreakNode:
+		p.branch("break", s.Label)
+	case ast.ContinueNode:
+		p.branch("continue", s.Label)
+	case ast.FallthroughStmt:
+		p.write("fallthrough")
+	case ast.VarDecl:
+		p.valueDecl("var", s.Grouped, s.Span, s.Specs)
+	case ast.ConstDecl:
+		p.valueDecl("const", s.Grouped, s.Span, s.Specs)
+	case ast.IfStmt:
+		p.ifStmt(s)
+	case ast.SwitchStmt:
+		p.switchStmt(s)
+	case ast.ForStmt:
+		p.forStmt(s)
+	case ast.RangeStmt:
+		p.rangeStmt(s)
+	case ast.LabeledStmt:
+		p.write(s.Label, ": ")
+		p.stmt(s.Stmt)
+	}
+}
+
+func (p *printer) branch(keyword, label string) {
+	p.write(keyword)
+	if label != "" {
+		p.write(" ", label)
+	}
+}
+
+func (p *printer) ifStmt(s ast.IfStmt) {
+	p.write("if ")
+	p.expr(s.Cond)
+	p.write(" ")
+	close := p.block(s.Then, s.Cond.NodeSpan().End.Offset)
+	switch {
+	case s.ElseIf != nil:
+		p.write(" else ")
+		p.ifStmt(*s.ElseIf)
+	case s.Else != nil:
+		p.write(" else ")
+		p.block(s.Else, close.End.Offset)
+	}
+}
+
+func (p *printer) switchStmt(s ast.SwitchStmt) {
+	p.write("switch ")
+	from := s.Pos.Offset
+	if s.Tag != nil {
+		p.expr(s.Tag)
+		p.write(" ")
+		from = s.Tag.NodeSpan().End.Offset
+	}
+	open, close := p.braces(token.Delimiter.LBrace, token.Delimiter.RBrace, from)
+	if len(s.Cases) == 0 && !p.hasComments(open.Offset, close.Offset) {
+		p.write("{}")
+		return
+	}
+	p.write("{")
+	p.endLine(open.End)
+	p.indent++
+	p.lastLine = 0
+	for _, c := range s.Cases {
+		// case lines sit at the switch's own indent, like in Go
+		p.indent--
+		p.startLine(c.Pos, false)
+		colonFrom := c.Pos.Offset
+		if c.Default {
+			p.write("default:")
+		} else {
+			p.write("case ")
+			p.exprList(c.List)
+			p.write(":")
+			colonFrom = c.List[len(c.List)-1].NodeSpan().End.Offset
+		}
+		colon, _ := p.next(token.Delimiter.Colon, colonFrom)
+		p.endLine(colon.End)
+		p.indent++
+		for _, s := range c.Body {
+			p.stmtLine(s)
+		}
+	}
+	p.closeBrace(close, "}")
+}
+
+func (p *printer) forStmt(s ast.ForStmt) {
+	p.write("for ")
+	from := s.Pos.Offset
+	for _, part := range []ast.Node{s.Init, s.Cond, s.Post} {
+		if part != nil {
+			from = part.NodeSpan().End.Offset
+		}
+	}
+	switch {
+	case s.Init == nil && s.Post == nil:
+		if s.Cond != nil {
+			p.expr(s.Cond)
+			p.write(" ")
+		}
+	default:
+		if s.Init != nil {
+			p.stmt(s.Init)
+		}
+		p.write("; ")
+		if s.Cond != nil {
+			p.expr(s.Cond)
+		}
+		p.write("; ")
+		if s.Post != nil {
+			p.stmt(s.Post)
+			p.write(" ")
+		}
+	}
+	p.block(s.Body, from)
+}
+
+func (p *printer) rangeStmt(s ast.RangeStmt) {
+	p.write("for ")
+	if s.Key != nil {
+		p.expr(s.Key)
+		if s.Value != nil {
+			p.write(", ")
+			p.expr(s.Value)
+		}
+		if s.Define {
+			p.write(" := ")
+		} else {
+			p.write(" = ")
+		}
+	}
+	p.write("range ")
+	p.expr(s.X)
+	p.write(" ")
+	p.block(s.Body, s.X.NodeSpan().End.Offset)
+}
+
+// ================= Expressions =================
+
+func (p *printer) exprList(list []ast.Expression) {
+	for i, x := range list {
+		if i > 0 {
+			p.comma(list[i-1].NodeSpan().End.Offset)
+		}
+		p.expr(x)
+	}
+}
+
+// comma writes the ", " of a list after the comments between the element
+// ending at end and its comma.
+func (p *printer) comma(end int) {
+	comma, _ := p.next(token.Delimiter.Comma, end)
+	p.trail(comma.Offset)
+	p.write(", ")
+}
+
+func (p *printer) expr(x ast.Expression) {
+	p.exprDepth(x, 0)
+}
+
+// exprDepth prints x; depth is how deep x sits inside index brackets,
+// where additive and multiplicative operators lose their spaces.
+func (p *printer) exprDepth(x ast.Expression, depth int) {
+	if x != nil {
+		p.inline(x.NodeSpan().Pos.Offset)
+	}
+	switch x := x.(type) {
+	case ast.IdentExpr:
+		p.write(x.Name)
+	case ast.NumberExpr:
+		p.write(x.Literal, x.Suffix)
+	case ast.StringExpr, ast.RuneExpr:
+		p.write(p.source(x.NodeSpan()))
+	case ast.ParenExpr:
+		p.write("(")
+		p.expr(x.X)
+		p.write(")")
+	case ast.UnaryExpr:
+		p.write(x.Op.Value)
+		p.writeAfter(x.Op.Value, p.capture(func() { p.exprDepth(x.Expr, depth) }))
+	case ast.BinaryExpr:
+		p.binary(x, cutoff(x, depth))
+	case ast.CallExpr:
+		p.exprDepth(x.Func, depth)
+		p.call(x)
+	case ast.SelectorExpr:
+		p.exprDepth(x.X, depth)
+		p.write(".", x.Sel)
+	case ast.IndexExpr:
+		p.exprDepth(x.X, depth)
+		p.write("[")
+		p.exprDepth(x.Index, depth+1)
+		p.write("]")
+	case ast.SliceExpr:
+		p.exprDepth(x.X, depth)
+		p.write("[")
+		for i, index := range []ast.Expression{x.Low, x.High, x.Max} {
+			if i == 2 && !x.Slice3 {
+				break
+			}
+			if i > 0 {
+				p.write(":")
+			}
+			if index != nil {
+				p.exprDepth(index, depth+1)
+			}
+		}
+		p.write("]")
+	case ast.CompositeLit:
+		p.compositeLit(x)
+	case ast.KeyValueExpr:
+		p.expr(x.Key)
+		p.write(": ")
+		p.expr(x.Value)
+	}
+}
+
+// cutoff is the precedence from which the operators of the binary
+// expression x are written without spaces. As in gofmt only a mix of
+// additive and multiplicative operators tightens, as in a + b*c; inside
+// an index both kinds always do.
+func cutoff(x ast.BinaryExpr, depth int) int {
+	if depth > 0 {
+		return 4
+	}
+	levels := map[int]bool{}
+	var collect func(e ast.Expression)
+	collect = func(e ast.Expression) {
+		if b, ok := e.(ast.BinaryExpr); ok {
+			levels[token.Precedence(b.Op.Type)] = true
+			collect(b.Left)
+			collect(b.Right)
+		}
+	}
+	collect(x)
+	if levels[4] && levels[5] {
+		return 5
+	}
+	return 6
+}
+
+func (p *printer) binary(x ast.BinaryExpr, cut int) {
+	operand := func(e ast.Expression) {
+		if b, ok := e.(ast.BinaryExpr); ok {
+			p.binary(b, cut)
+		} else {
+			p.expr(e)
+		}
+	}
+	operand(x.Left)
+	p.trail(x.Op.Offset)
+	if token.Precedence(x.Op.Type) < cut {
+		p.write(" ", x.Op.Value, " ")
+		operand(x.Right)
+		return
+	}
+	p.write(x.Op.Value)
+	p.writeAfter(x.Op.Value, p.capture(func() { operand(x.Right) }))
+}
+
+// writeAfter writes s right after the operator op, with a space between
+// them where they would otherwise run into another token, as in - -x,
+// a&^b or a/ *p.
+func (p *printer) writeAfter(op, s string) {
+	if s != "" {
+		switch op[len(op)-1:] + s[:1] {
+		case "++", "--", "&&", "&^", "||", "<<", ">>", "<=", ">=", "==", "!=",
+			"+=", "-=", "*=", "/=", "%=", ":=", "/*", "//":
+			p.write(" ")
+		}
+	}
+	p.write(s)
+}
+
+// call prints the arguments of x in parens, breaking the lines where the
+// source did. A ')' on a line of its own stays there, after a comma.
+// Calls opened on the same line share one level of indent: after
+// g(a, k( the arguments of k go one tab in, not two.
+func (p *printer) call(x ast.CallExpr) {
+	open, close := p.braces(token.Delimiter.LParen, token.Delimiter.RParen, x.Func.NodeSpan().End.Offset)
+	p.write("(")
+	if open.Line == p.callLine {
+		p.indent--
+		defer func() { p.indent++ }()
+	} else {
+		defer func(line int) { p.callLine = line }(p.callLine)
+		p.callLine = open.Line
+	}
+	p.indent++
+	end := open.End
+	for i, arg := range x.Args {
+		span := arg.NodeSpan()
+		if span.Pos.Line > end.Line {
+			if i > 0 {
+				comma, _ := p.next(token.Delimiter.Comma, x.Args[i-1].NodeSpan().End.Offset)
+				p.trail(comma.Offset)
+				p.write(",")
+				end = comma.End
+			}
+			p.endLine(end)
+			p.startLine(span.Pos, false)
+		} else if i > 0 {
+			p.comma(x.Args[i-1].NodeSpan().End.Offset)
+		}
+		p.expr(arg)
+		end = span.End
+	}
+	if n := len(x.Args); n > 0 && close.Line > end.Line {
+		comma, _ := p.next(token.Delimiter.Comma, end.Offset)
+		if comma.Offset < close.Offset {
+			p.trail(comma.Offset)
+			end = comma.End
+		}
+		p.write(",")
+		p.endLine(end)
+		p.closeBrace(close, ")")
+		return
+	}
+	p.indent--
+	p.trail(close.Offset)
+	p.write(")")
+}
+
+// compositeLit prints T{elts}, keeping one element per line when the
+// source broke the literal over lines.
+func (p *printer) compositeLit(x ast.CompositeLit) {
+	from := x.Pos.Offset
+	if x.Type != nil {
+		p.expr(x.Type)
+		from = x.Type.NodeSpan().End.Offset
+	}
+	open, close := p.braces(token.Delimiter.LBrace, token.Delimiter.RBrace, from)
+	multiline := p.hasComments(open.Offset, close.Offset)
+	for _, elt := range x.Elts {
+		if elt.NodeSpan().Pos.Line > open.Line {
+			multiline = true
+		}
+	}
+	if !multiline {
+		p.write("{")
+		p.exprList(x.Elts)
+		p.write("}")
+		return
+	}
+
+	// one element per line, the values of key: value pairs aligned; the
+	// comments after an element on the line of '{' go with the element
+	p.write("{")
+	if len(x.Elts) == 0 || x.Elts[0].NodeSpan().Pos.Line > open.Line {
+		p.endLine(open.End)
+	}
+	p.indent++
+	p.lastLine = 0
+	rows := make([]row, len(x.Elts))
+	for i, elt := range x.Elts {
+		span := elt.NodeSpan()
+		if comma, _ := p.next(token.Delimiter.Comma, span.End.Offset); comma.Line == span.End.Line {
+			span.End = comma.End
+		}
+		rows[i].lead = p.lead(span.Pos)
+		if kv, ok := elt.(ast.KeyValueExpr); ok {
+			key := p.capture(func() { p.expr(kv.Key) })
+			value := p.capture(func() { p.expr(kv.Value) })
+			rows[i].cells = []string{key + ":", value + ","}
+		} else {
+			rows[i].cells = []string{p.capture(func() { p.expr(elt) }) + ","}
+		}
+		rows[i].span = span
+		rows[i].trailing = p.trailing(span.End)
+	}
+	p.rows(rows)
+	p.closeBrace(close, "}")
+}
+
+// ================= Types =================
+
+func (p *printer) typ(t ast.TypeExpr) {
+	if t != nil {
+		p.inline(t.NodeSpan().Pos.Offset)
+	}
+	switch t := t.(type) {
+	case ast.NamedType:
+		p.write(t.Name)
+	case ast.QualifiedType:
+		p.write(t.Pkg, ".", t.Name)
+	case ast.PointerType:
+		p.write("*")
+		p.typ(t.Elem)
+	case ast.ArrayType:
+		p.write("[")
+		p.expr(t.Len)
+		p.write("]")
+		p.typ(t.Elem)
+	case ast.SliceType:
+		p.write("[]")
+		p.typ(t.Elem)
+	case ast.MapType:
+		p.write("map[")
+		p.typ(t.Key)
+		p.write("]")
+		p.typ(t.Value)
+	case ast.OwnType:
+		p.write("own[")
+		p.typ(t.Elem)
+		p.write("]")
+	case ast.FuncType:
+		p.write("func(")
+		p.sigList(sigs(t.Params))
+		p.write(")")
+		if len(t.Results) > 0 {
+			p.write(" ")
+			p.results(t.Results, false)
+		}
+	case ast.TupleType:
+		p.write("(")
+		p.sigList(t.Elems)
+		p.write(")")
+	case ast.StructType:
+		p.structType(t)
+	}
+}
+
+func (p *printer) structType(t ast.StructType) {
+	open, close := p.braces(token.Delimiter.LBrace, token.Delimiter.RBrace, t.Pos.Offset)
+	p.inline(open.Offset)
+	if len(t.Fields) == 0 && !p.hasComments(open.Offset, close.Offset) {
+		p.emptyBraces(open, close, "struct{}")
+		return
+	}
+	p.write("struct {")
+	p.endLine(open.End)
+	p.indent++
+	p.lastLine = 0
+	rows := make([]row, len(t.Fields))
+	for i, field := range t.Fields {
+		lead := p.lead(field.Pos)
+		typ := p.capture(func() { p.typ(field.Type) })
+		cells := []string{typ}
+		if !field.Embedded {
+			cells = []string{strings.Join(field.Names, ", "), typ}
+		}
+		if field.Tag != "" {
+			tag, _ := p.next(token.OtherLiteral.String, field.Type.NodeSpan().End.Offset)
+			cells = append(cells, p.source(tag.Span()))
+		}
+		rows[i] = row{span: field.Span, lead: lead, cells: cells, trailing: p.trailing(field.End)}
+	}
+	p.rows(rows)
+	p.closeBrace(close, "}")
+}
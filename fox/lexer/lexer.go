@@ -0,0 +1,271 @@
+// Package lexer turns Fox source into tokens.
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fox/diag"
+	"fox/token"
+)
+
+// Tokenize splits input into tokens, ending with an EOF token. Newlines
+// that end a statement become ';' tokens with the Value "\n", comments
+// hang on the tokens around them, and malformed input is reported and
+// kept as ILLEGAL tokens so that parsing can go on.
+func Tokenize(input string) ([]token.Token, []diag.Diagnostic) {
+	var tokens []token.Token
+	var diags []diag.Diagnostic
+	var current strings.Builder
+	var pending []token.Comment // comments waiting for the next token
+	line, col := 1, 0
+
+	// where the identifier in current started
+	curLine, curCol, curOff := 0, 0, 0
+
+	// emit appends tok, whose source text ends at byte offset end
+	emit := func(tok token.Token, end int) {
+		text := input[tok.Offset:end]
+		tok.End = token.Position{Offset: end, Line: tok.Line, Column: tok.Column + utf8.RuneCountInString(text)}
+		if nl := strings.LastIndexByte(text, '\n'); nl >= 0 {
+			tok.End.Line += strings.Count(text, "\n")
+			tok.End.Column = utf8.RuneCountInString(text[nl+1:]) + 1
+		}
+		tok.Leading = pending
+		pending = nil
+		tokens = append(tokens, tok)
+	}
+
+	report := func(code string, tok token.Token, end int, msg string) {
+		span := token.Span{Pos: tok.Pos(), End: token.Position{Offset: end, Line: tok.Line, Column: tok.Column + utf8.RuneCountInString(input[tok.Offset:end])}}
+		diags = append(diags, diag.New(code, span, msg))
+	}
+
+	// a comment sharing a line with the previous token trails it,
+	// anything else leads the next token
+	addComment := func(c token.Comment) {
+		if len(pending) == 0 && len(tokens) > 0 && tokens[len(tokens)-1].Line == c.Line {
+			last := &tokens[len(tokens)-1]
+			last.Trailing = append(last.Trailing, c)
+			return
+		}
+		pending = append(pending, c)
+	}
+
+	// automatic semicolon insertion, as in Go: a newline ends the line's
+	// statement when its last token could end one
+	insertSemi := func(offset, line, col int) {
+		if len(tokens) == 0 {
+			return
+		}
+		switch tokens[len(tokens)-1].Type {
+		case token.Ident.Ident, token.NumericLiteral.Int, token.NumericLiteral.Float, token.OtherLiteral.String, token.OtherLiteral.Rune,
+			token.Delimiter.RParen, token.Delimiter.RBrace, token.Delimiter.RBrack,
+			token.Keyword.Return, token.Keyword.Break, token.Keyword.Continue, token.Keyword.Fallthrough, token.Operator.Inc, token.Operator.Dec,
+			token.Special.Illegal:
+			tokens = append(tokens, token.Token{
+				Kind:   token.DelimiterKind,
+				Type:   token.Delimiter.Semic,
+				Value:  "\n",
+				Offset: offset,
+				Line:   line,
+				Column: col,
+				End:    token.Position{Offset: offset, Line: line, Column: col},
+			})
+		}
+	}
+
+	// text that cannot start any token
+	illegal := func(code string, offset, end int, msg string) {
+		tok := token.Token{Kind: token.SpecialKind, Type: token.Special.Illegal, Value: input[offset:end], Offset: offset, Line: line, Column: col}
+		emit(tok, end)
+		report(code, tok, end, msg)
+	}
+
+	addToken := func() {
+		if current.Len() == 0 {
+			return
+		}
+		val := current.String()
+		current.Reset()
+
+		tok := token.Token{Kind: token.IdentKind, Type: token.Ident.Ident, Value: val, Offset: curOff, Line: curLine, Column: curCol}
+		if typ, ok := token.Lookup(val); ok {
+			tok.Kind, tok.Type = token.KeywordKind, typ
+		}
+		emit(tok, curOff+len(val))
+	}
+
+	i := 0
+	// a leading byte order mark is not part of the source
+	if strings.HasPrefix(input, "\uFEFF") {
+		i = len("\uFEFF")
+	}
+
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		col++
+
+		if r == '\n' {
+			addToken()
+			insertSemi(i, line, col)
+			line++
+			col = 0
+			i++
+			continue
+		}
+
+		if unicode.IsSpace(r) {
+			addToken()
+			i += size
+			continue
+		}
+
+		// line comment: runs to the end of the line, newline not included
+		if r == '/' && i+1 < len(input) && input[i+1] == '/' {
+			addToken()
+			start, startCol := i, col
+			for i < len(input) && input[i] != '\n' {
+				i++
+			}
+			text := input[start:i]
+			col += utf8.RuneCountInString(text) - 1
+			addComment(token.Comment{Text: text, Offset: start, Line: line, Column: startCol})
+			continue
+		}
+
+		// block comment: may span lines
+		if r == '/' && i+1 < len(input) && input[i+1] == '*' {
+			addToken()
+			start, startLine, startCol := i, line, col
+			if end := strings.Index(input[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				report(diag.CodeBadLiteral, token.Token{Offset: start, Line: line, Column: col}, start+2, "unterminated block comment")
+				i = len(input)
+			}
+			text := input[start:i]
+			if nl := strings.LastIndexByte(text, '\n'); nl >= 0 {
+				line += strings.Count(text, "\n")
+				col = utf8.RuneCountInString(text[nl+1:])
+			} else {
+				col += utf8.RuneCountInString(text) - 1
+			}
+			addComment(token.Comment{Text: text, Offset: start, Line: startLine, Column: startCol})
+			if line != startLine {
+				// a comment spanning lines acts like a newline
+				insertSemi(start, startLine, startCol)
+			}
+			continue
+		}
+
+		// two-char operators; an operator's type is its own spelling
+		if i+1 < len(input) {
+			two := input[i : i+2]
+			switch two {
+			case token.Operator.Define, token.Operator.Eq, token.Operator.Neq, token.Operator.Lte, token.Operator.Gte,
+				token.Operator.And, token.Operator.Or, token.Operator.PlusAssign, token.Operator.MinusAssign,
+				token.Operator.StarAssign, token.Operator.SlashAssign, token.Operator.PercentAssign,
+				token.Operator.Inc, token.Operator.Dec, token.Operator.Shl, token.Operator.Shr, token.Operator.AndNot:
+				addToken()
+				emit(token.Token{Kind: token.OperatorKind, Type: two, Value: two, Offset: i, Line: line, Column: col}, i+2)
+				i += 2
+				col++
+				continue
+			}
+		}
+
+		// numbers start with a digit, or a dot followed by one (.5)
+		if current.Len() == 0 && (isDigit(input[i]) ||
+			(r == '.' && i+1 < len(input) && isDigit(input[i+1]))) {
+			start := i
+			tok, err := readNumber(input, &i)
+			if err != nil {
+				// skip the rest of the malformed literal
+				for i < len(input) && (isLetterOrDigit(input[i]) || input[i] == '_' || input[i] == '.') {
+					i++
+				}
+				illegal(diag.CodeBadLiteral, start, i, err.Error())
+			} else {
+				tok.Offset, tok.Line, tok.Column = start, line, col
+				emit(tok, i)
+			}
+			col += i - start - 1
+			continue
+		}
+
+		switch r {
+		case '=', '+', '-', '*', '/', '%', '&', '|', '^', '!', '<', '>':
+			addToken()
+			emit(token.Token{Kind: token.OperatorKind, Type: string(r), Value: string(r), Offset: i, Line: line, Column: col}, i+1)
+			i++
+			continue
+		case '(', ')', '{', '}', '[', ']', ',', ';', ':', '.':
+			addToken()
+			emit(token.Token{Kind: token.DelimiterKind, Type: string(r), Value: string(r), Offset: i, Line: line, Column: col}, i+1)
+			i++
+			continue
+		case '"', '`', '\'':
+			addToken()
+			start, startCol := i, col
+			var val, typ string
+			var err error
+			switch r {
+			case '"':
+				typ = token.OtherLiteral.String
+				val, err = readString(input, &i)
+			case '`':
+				typ = token.OtherLiteral.String
+				val, err = readRawString(input, &i)
+			default:
+				typ = token.OtherLiteral.Rune
+				var ch rune
+				ch, err = readRune(input, &i)
+				val = string(ch)
+			}
+			// a bad literal is still a literal, the parser can go on
+			tok := token.Token{Kind: token.OtherLiteralKind, Type: typ, Value: val, Offset: start, Line: line, Column: startCol}
+			if err != nil {
+				report(diag.CodeBadLiteral, tok, i, err.Error())
+			}
+			emit(tok, i)
+
+			// raw strings may cross lines
+			lit := input[start:i]
+			if nl := strings.LastIndexByte(lit, '\n'); nl >= 0 {
+				line += strings.Count(lit, "\n")
+				col = utf8.RuneCountInString(lit[nl+1:])
+			} else {
+				col += utf8.RuneCountInString(lit) - 1
+			}
+			continue
+		}
+
+		// identifiers: a letter or '_' followed by letters and digits
+		switch {
+		case r == utf8.RuneError && size == 1:
+			addToken()
+			illegal(diag.CodeIllegalChar, i, i+1, "invalid UTF-8 encoding")
+		case isIdentStart(r) || (current.Len() > 0 && unicode.IsDigit(r)):
+			if current.Len() == 0 {
+				curLine, curCol, curOff = line, col, i
+			}
+			current.WriteRune(r)
+		case unicode.IsDigit(r):
+			illegal(diag.CodeIllegalChar, i, i+size, fmt.Sprintf("identifier cannot begin with digit %q", r))
+		default:
+			addToken()
+			illegal(diag.CodeIllegalChar, i, i+size, fmt.Sprintf("invalid character %q", r))
+		}
+		i += size
+	}
+
+	addToken()
+	insertSemi(len(input), line, col+1)
+
+	// EOF closes every stream and picks up the comments after the last token
+	emit(token.Token{Kind: token.SpecialKind, Type: token.Special.EOF, Offset: len(input), Line: line, Column: col + 1}, len(input))
+	return tokens, diags
+}
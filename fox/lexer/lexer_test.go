@@ -0,0 +1,44 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"fox/lexer"
+	"fox/token"
+)
+
+// A literal with a bad escape is reported once and lexing goes on after
+// its closing quote.
+func TestBadEscapes(t *testing.T) {
+	tests := []struct {
+		src, msg string
+	}{
+		{`"a\qb c" + 1`, `unknown escape sequence \q`},
+		{`"a\xZZ b" + 1`, `invalid character 'Z' in hex escape`},
+		{`"a\u00zz \" b" + 1`, `invalid character 'z' in hex escape`},
+		{`'\q' + 1`, `unknown escape sequence \q`},
+		{`'\x4' + 1`, `invalid character '\'' in hex escape`},
+	}
+	for _, tt := range tests {
+		tokens, diags := lexer.Tokenize(tt.src)
+		if len(diags) != 1 || diags[0].Msg != tt.msg {
+			t.Errorf("%s: got %v, want one error %q", tt.src, diags, tt.msg)
+			continue
+		}
+		var types []string
+		for _, tok := range tokens {
+			types = append(types, tok.Type)
+		}
+		want := []string{tokens[0].Type, token.Operator.Plus, token.NumericLiteral.Int, token.Delimiter.Semic, token.Special.EOF}
+		if len(types) != len(want) {
+			t.Errorf("%s: tokens %v, want %v", tt.src, types, want)
+			continue
+		}
+		for i := range want {
+			if types[i] != want[i] {
+				t.Errorf("%s: tokens %v, want %v", tt.src, types, want)
+				break
+			}
+		}
+	}
+}
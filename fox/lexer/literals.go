@@ -0,0 +1,361 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fox/token"
+)
+
+// numeric literal suffixes; an integer may be typed as a float, a float
+// never as an integer
+var numberSuffixes = map[string]bool{
+	"i8": true, "i16": true, "i32": true, "i64": true,
+	"u8": true, "u16": true, "u32": true, "u64": true,
+	"f32": true, "f64": true,
+}
+
+// readNumber scans a numeric literal at *pos: decimal, 0x/0o/0b prefixed
+// or legacy 0-octal integers, decimal floats with fraction and exponent,
+// `_` separators between digits and an optional type suffix (10u8,
+// 2.0f32). The token's Value is the literal without the suffix.
+func readNumber(src string, pos *int) (token.Token, error) {
+	start := *pos
+	base := 10
+	isFloat := false
+
+	if *pos+1 < len(src) && src[*pos] == '0' {
+		switch src[*pos+1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 10 {
+			*pos += 2
+		}
+	}
+
+	digitsStart := *pos
+	readDigits(src, pos, base)
+	if base != 10 && *pos == digitsStart {
+		return token.Token{}, fmt.Errorf("%s literal has no digits", baseName(base))
+	}
+
+	if base == 10 {
+		// fraction
+		if *pos < len(src) && src[*pos] == '.' {
+			isFloat = true
+			*pos++
+			readDigits(src, pos, 10)
+		}
+		// exponent
+		if *pos < len(src) && (src[*pos] == 'e' || src[*pos] == 'E') {
+			isFloat = true
+			*pos++
+			if *pos < len(src) && (src[*pos] == '+' || src[*pos] == '-') {
+				*pos++
+			}
+			expStart := *pos
+			readDigits(src, pos, 10)
+			if *pos == expStart {
+				return token.Token{}, errors.New("exponent has no digits")
+			}
+		}
+	}
+
+	value := src[start:*pos]
+	if err := checkSeparators(value, base); err != nil {
+		return token.Token{}, err
+	}
+
+	// 0755: legacy octal
+	if base == 10 && !isFloat && len(value) > 1 && value[0] == '0' {
+		for _, c := range value {
+			if c > '7' {
+				return token.Token{}, fmt.Errorf("invalid digit %q in octal literal", c)
+			}
+		}
+	}
+
+	// suffix
+	sufStart := *pos
+	for *pos < len(src) && isLetterOrDigit(src[*pos]) {
+		*pos++
+	}
+	suffix := src[sufStart:*pos]
+
+	if suffix != "" && !numberSuffixes[suffix] {
+		return token.Token{}, fmt.Errorf("invalid suffix %q on numeric literal %s", suffix, value)
+	}
+	if isFloat && (strings.HasPrefix(suffix, "i") || strings.HasPrefix(suffix, "u")) {
+		return token.Token{}, fmt.Errorf("invalid numeric literal: float cannot have integer suffix: %s%s", value, suffix)
+	}
+
+	typ := token.NumericLiteral.Int
+	if isFloat || strings.HasPrefix(suffix, "f") {
+		typ = token.NumericLiteral.Float
+	}
+
+	return token.Token{
+		Kind:   token.NumericLiteralKind,
+		Type:   typ,
+		Value:  value,
+		Suffix: suffix,
+	}, nil
+}
+
+// readDigits skips digits of the given base and `_` separators. Digits
+// beyond the base (9 in an octal literal) are left for the suffix check.
+func readDigits(src string, pos *int, base int) {
+	for *pos < len(src) {
+		c := src[*pos]
+		if c != '_' && (hexVal(c) < 0 || hexVal(c) >= base) {
+			return
+		}
+		*pos++
+	}
+}
+
+// checkSeparators reports a `_` that does not sit between two digits;
+// one directly after a base prefix (0x_ff) is fine, like in Go.
+func checkSeparators(lit string, base int) error {
+	isDigitOf := func(c byte) bool { return hexVal(c) >= 0 && hexVal(c) < base }
+	for i := 0; i < len(lit); i++ {
+		if lit[i] != '_' {
+			continue
+		}
+		prevOK := i > 0 && (isDigitOf(lit[i-1]) || (i == 2 && base != 10))
+		nextOK := i+1 < len(lit) && isDigitOf(lit[i+1])
+		if !prevOK || !nextOK {
+			return fmt.Errorf("'_' must separate successive digits in %s", lit)
+		}
+	}
+	return nil
+}
+
+func baseName(base int) string {
+	switch base {
+	case 16:
+		return "hexadecimal"
+	case 8:
+		return "octal"
+	case 2:
+		return "binary"
+	}
+	return "decimal"
+}
+
+// readString scans an interpreted "..." literal. *pos is at the opening
+// quote and is left just past the closing one; escapes are decoded.
+func readString(src string, pos *int) (string, error) {
+	*pos++
+	var s strings.Builder
+	for {
+		if *pos >= len(src) || src[*pos] == '\n' {
+			return "", errors.New("unterminated string literal")
+		}
+		c := src[*pos]
+		if c == '"' {
+			*pos++
+			return s.String(), nil
+		}
+		if c != '\\' {
+			s.WriteByte(c)
+			*pos++
+			continue
+		}
+		r, isByte, err := readEscape(src, pos, '"')
+		if err != nil {
+			skipLiteral(src, pos, '"')
+			return "", err
+		}
+		if isByte {
+			s.WriteByte(byte(r))
+		} else {
+			s.WriteRune(r)
+		}
+	}
+}
+
+// skipLiteral moves *pos past the rest of a literal with a bad escape, up
+// to its closing quote or the end of the line, so lexing goes on after it.
+func skipLiteral(src string, pos *int, quote byte) {
+	for *pos < len(src) && src[*pos] != '\n' {
+		switch src[*pos] {
+		case quote:
+			*pos++
+			return
+		case '\\':
+			if *pos+1 < len(src) && src[*pos+1] != '\n' {
+				*pos++
+			}
+		}
+		*pos++
+	}
+}
+
+// readRawString scans a `...` literal, which may span lines and has no
+// escapes. Carriage returns are dropped like in Go.
+func readRawString(src string, pos *int) (string, error) {
+	end := strings.IndexByte(src[*pos+1:], '`')
+	if end < 0 {
+		*pos = len(src)
+		return "", errors.New("unterminated raw string literal")
+	}
+	raw := src[*pos+1 : *pos+1+end]
+	*pos += end + 2
+	return strings.ReplaceAll(raw, "\r", ""), nil
+}
+
+// readRune scans a '...' literal holding exactly one character and
+// returns that character.
+func readRune(src string, pos *int) (rune, error) {
+	*pos++
+	if *pos >= len(src) || src[*pos] == '\n' {
+		return 0, errors.New("unterminated rune literal")
+	}
+
+	var r rune
+	switch src[*pos] {
+	case '\'':
+		return 0, errors.New("empty rune literal")
+	case '\\':
+		var err error
+		r, _, err = readEscape(src, pos, '\'')
+		if err != nil {
+			skipLiteral(src, pos, '\'')
+			return 0, err
+		}
+	default:
+		var size int
+		r, size = utf8.DecodeRuneInString(src[*pos:])
+		*pos += size
+	}
+
+	if *pos >= len(src) || src[*pos] != '\'' {
+		for *pos < len(src) && src[*pos] != '\'' && src[*pos] != '\n' {
+			*pos++
+		}
+		if *pos < len(src) && src[*pos] == '\'' {
+			*pos++
+			return 0, errors.New("more than one character in rune literal")
+		}
+		return 0, errors.New("unterminated rune literal")
+	}
+	*pos++
+	return r, nil
+}
+
+// readEscape decodes one escape sequence; *pos is at the backslash.
+// isByte reports a \x or octal escape, which stands for a raw byte
+// rather than a code point.
+func readEscape(src string, pos *int, quote byte) (r rune, isByte bool, err error) {
+	*pos++
+	if *pos >= len(src) {
+		return 0, false, errors.New("unterminated escape sequence")
+	}
+	c := src[*pos]
+	*pos++
+
+	switch c {
+	case 'a':
+		return '\a', false, nil
+	case 'b':
+		return '\b', false, nil
+	case 'f':
+		return '\f', false, nil
+	case 'n':
+		return '\n', false, nil
+	case 'r':
+		return '\r', false, nil
+	case 't':
+		return '\t', false, nil
+	case 'v':
+		return '\v', false, nil
+	case '\\':
+		return '\\', false, nil
+	case quote:
+		return rune(quote), false, nil
+	case 'x':
+		r, err = readHex(src, pos, 2)
+		return r, true, err
+	case 'u':
+		r, err = readHex(src, pos, 4)
+	case 'U':
+		r, err = readHex(src, pos, 8)
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		r = rune(c - '0')
+		for n := 0; n < 2; n++ {
+			if *pos >= len(src) || src[*pos] < '0' || src[*pos] > '7' {
+				return 0, false, errors.New("octal escape needs 3 digits")
+			}
+			r = r*8 + rune(src[*pos]-'0')
+			*pos++
+		}
+		if r > 255 {
+			return 0, false, errors.New("octal escape value > 255")
+		}
+		return r, true, nil
+	default:
+		return 0, false, fmt.Errorf("unknown escape sequence \\%c", c)
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+	if r > utf8.MaxRune || (r >= 0xD800 && r < 0xE000) {
+		return 0, false, errors.New("escape sequence is invalid Unicode code point")
+	}
+	return r, false, nil
+}
+
+func readHex(src string, pos *int, n int) (rune, error) {
+	var r rune
+	for k := 0; k < n; k++ {
+		if *pos >= len(src) {
+			return 0, errors.New("unterminated escape sequence")
+		}
+		d := hexVal(src[*pos])
+		if d < 0 {
+			return 0, fmt.Errorf("invalid character %q in hex escape", src[*pos])
+		}
+		r = r*16 + rune(d)
+		*pos++
+	}
+	return r, nil
+}
+
+func hexVal(b byte) int {
+	switch {
+	case b >= '0' && b <= '9':
+		return int(b - '0')
+	case b >= 'a' && b <= 'f':
+		return int(b-'a') + 10
+	case b >= 'A' && b <= 'F':
+		return int(b-'A') + 10
+	}
+	return -1
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isLetterOrDigit(b byte) bool {
+	return isLetter(b) || isDigit(b)
+}
+
+// identifiers follow Go: any Unicode letter or '_' may start one
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
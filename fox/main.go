@@ -2,18 +2,10 @@ package main
 
 import (
 	"os"
+
+	"fox/driver"
 )
 
 func main() {
-	// Read the Fox file
-	testFile := os.Args[1]
-	data, err := os.ReadFile("../" + testFile)
-	if err != nil {
-		panic(err)
-	}
-
-	content := string(data)
-	tokens := tokenize(content)
-
-	astBuilder(tokens)
+	os.Exit(driver.Run(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+
+	"fox/diag"
+	"fox/token"
+)
+
+// bailout unwinds the parser to the nearest recovery point: a statement
+// in parseStatement or a declaration in parseDecl.
+type bailout struct{}
+
+// report records a syntax error at tok and lets the parser go on. Only the
+// first error on a line is kept, the rest are usually fallout from it, and
+// ILLEGAL tokens were already reported by the lexer.
+func (p *parser) report(tok token.Token, code string, format string, args ...any) {
+	if tok.Type == token.Special.Illegal {
+		return
+	}
+	if n := len(p.diags); n > 0 && p.diags[n-1].Line == tok.Line {
+		return
+	}
+	if tok.Type == token.Special.EOF {
+		code = diag.CodeUnexpectedEOF
+	}
+	p.diags = append(p.diags, diag.New(code, tok.Span(), fmt.Sprintf(format, args...)))
+}
+
+// reportSpan records an error found after parsing, when only the span
+// of the node is left.
+func (p *parser) reportSpan(span token.Span, code string, format string, args ...any) {
+	p.diags = append(p.diags, diag.New(code, span, fmt.Sprintf(format, args...)))
+}
+
+// errorAt records a syntax error at tok and abandons the current statement
+// or declaration.
+func (p *parser) errorAt(tok token.Token, code string, format string, args ...any) {
+	p.report(tok, code, format, args...)
+	panic(bailout{})
+}
+
+// recovered reports whether r, the value of recover(), is a bailout.
+// Any other panic is a bug and keeps going.
+func recovered(r any) bool {
+	if r == nil {
+		return false
+	}
+	if _, ok := r.(bailout); !ok {
+		panic(r)
+	}
+	return true
+}
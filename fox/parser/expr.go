@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"fox/ast"
+	"fox/diag"
+	"fox/token"
+)
+
+// ================= Expressions =================
+
+// parse unary operators: -x +x !x ^x &x *p
+func (p *parser) parseUnary() ast.Expression {
+	op := p.tokens[p.pos]
+	start := p.pos
+
+	switch op.Type {
+	case token.Operator.Minus, token.Operator.Plus, token.Operator.Not, token.Operator.Caret, token.Operator.Amp, token.Operator.Star:
+		p.pos++
+		// &&x and **p would need a pointer to a pointer
+		if (op.Type == token.Operator.Amp || op.Type == token.Operator.Star) && p.tokens[p.pos].Type == op.Type {
+			p.reportPointerToPointer(p.tokens[p.pos])
+		}
+		expr := p.parseUnary()
+		return ast.UnaryExpr{Span: p.spanOf(start, p.pos), Op: op, Expr: expr}
+
+	case token.Operator.And:
+		// && here is two address-of operators lexed as one
+		p.reportPointerToPointer(op)
+		p.pos++
+		p.parseUnary()
+		return ast.BadExpr{Span: p.spanOf(start, p.pos)}
+	}
+	return p.parsePrimary()
+}
+
+// Fox has no pointers to pointers: every alias points at an object.
+func (p *parser) reportPointerToPointer(tok token.Token) {
+	p.report(tok, diag.CodePointerToPointer, "pointer to pointer is not allowed")
+}
+
+// parse binary operators of precedence minPrec and up by precedence
+// climbing; operators of one level associate to the left
+func (p *parser) parseBinary(minPrec int) ast.Expression {
+	left := p.parseUnary()
+	for {
+		op := p.tokens[p.pos]
+		prec := token.Precedence(op.Type)
+		if op.Kind != token.OperatorKind || prec == 0 || prec < minPrec {
+			return left
+		}
+		p.pos++
+		right := p.parseBinary(prec + 1)
+		left = ast.BinaryExpr{Span: p.spanFrom(left), Left: left, Op: op, Right: right}
+	}
+}
+
+// top-level expression
+func (p *parser) parseExpr() ast.Expression {
+	return p.parseBinary(1)
+}
+
+// primary expressions: an operand followed by selectors and indexing
+func (p *parser) parsePrimary() ast.Expression {
+	return p.parsePostfix(p.parseOperand())
+}
+
+func (p *parser) parseOperand() ast.Expression {
+	tok := p.tokens[p.pos]
+	start := p.pos
+	p.checkEOF(tok, "expression")
+
+	switch tok.Type {
+
+	case token.Ident.Ident:
+		p.pos++
+		return ast.IdentExpr{Span: p.spanOf(start, p.pos), Name: tok.Value}
+
+	case token.NumericLiteral.Int, token.NumericLiteral.Float:
+		p.pos++
+		return ast.NumberExpr{Span: p.spanOf(start, p.pos), Literal: tok.Value, Suffix: tok.Suffix}
+
+	case token.OtherLiteral.String:
+		p.pos++
+		return ast.StringExpr{Span: p.spanOf(start, p.pos), Literal: tok.Value}
+
+	case token.OtherLiteral.Rune:
+		p.pos++
+		return ast.RuneExpr{Span: p.spanOf(start, p.pos), Literal: tok.Value}
+
+	case token.Delimiter.LParen: //TOKEN_LPAREN:
+		p.pos++
+		p.exprLev++
+		expr := p.parseExpr()
+		p.exprLev--
+		p.expectType(token.Delimiter.RParen)
+		return ast.ParenExpr{Span: p.spanOf(start, p.pos), X: expr}
+
+	default:
+		// leave closing tokens for the caller to match
+		p.report(tok, diag.CodeSyntax, "expected expression, got %s", describe(tok))
+		switch tok.Type {
+		case token.Delimiter.RParen, token.Delimiter.RBrack, token.Delimiter.RBrace, token.Delimiter.Semic, token.Delimiter.Comma, token.Delimiter.Colon, token.Special.EOF,
+			token.Keyword.Case, token.Keyword.Default:
+		default:
+			p.pos++
+		}
+		return ast.BadExpr{Span: p.spanOf(start, start)}
+	}
+}
+
+// parse the postfix part of a primary expression: .sel, [i], [lo:hi],
+// (args) and T{elts}
+func (p *parser) parsePostfix(x ast.Expression) ast.Expression {
+	for {
+		switch p.tokens[p.pos].Type {
+		case token.Delimiter.Dot:
+			p.pos++
+			sel := p.expectIdent().Value
+			x = ast.SelectorExpr{Span: p.spanFrom(x), X: x, Sel: sel}
+
+		case token.Delimiter.LBrack:
+			x = p.parseIndexOrSlice(x)
+
+		case token.Delimiter.LParen:
+			x = p.parseCall(x)
+
+		case token.Delimiter.LBrace:
+			if p.exprLev < 0 || !isLiteralType(x) {
+				return x
+			}
+			x = p.parseCompositeLit(x)
+
+		default:
+			return x
+		}
+	}
+}
+
+func (p *parser) parseIndexOrSlice(x ast.Expression) ast.Expression {
+	p.expectType(token.Delimiter.LBrack)
+	p.exprLev++
+
+	// up to three indices separated by ':'
+	var index [3]ast.Expression
+	colons := 0
+	if p.tokens[p.pos].Type != token.Delimiter.Colon {
+		index[0] = p.parseExpr()
+	}
+	for colons < 2 && p.tokens[p.pos].Type == token.Delimiter.Colon {
+		colons++
+		p.pos++
+		if p.tokens[p.pos].Type != token.Delimiter.Colon && p.tokens[p.pos].Type != token.Delimiter.RBrack {
+			index[colons] = p.parseExpr()
+		}
+	}
+	p.exprLev--
+	rbrack := p.expectType(token.Delimiter.RBrack)
+
+	span := p.spanFrom(x)
+	switch colons {
+	case 0:
+		return ast.IndexExpr{Span: span, X: x, Index: index[0]}
+	case 2:
+		// x[lo:hi:max] needs both hi and max
+		if index[1] == nil || index[2] == nil {
+			p.errorAt(rbrack, diag.CodeSyntax, "middle and final index required in 3-index slice")
+		}
+		return ast.SliceExpr{Span: span, X: x, Low: index[0], High: index[1], Max: index[2], Slice3: true}
+	}
+	return ast.SliceExpr{Span: span, X: x, Low: index[0], High: index[1]}
+}
+
+// isLiteralType reports whether x can name the type of a composite
+// literal: T or pkg.T.
+func isLiteralType(x ast.Expression) bool {
+	switch x := x.(type) {
+	case ast.IdentExpr:
+		return true
+	case ast.SelectorExpr:
+		_, ok := x.X.(ast.IdentExpr)
+		return ok
+	}
+	return false
+}
+
+// parseCompositeLit parses {elts} after the literal type typ, which is
+// nil for an elided inner literal. Elements are positional values or
+// Key: Value pairs; either may itself be a braced literal.
+func (p *parser) parseCompositeLit(typ ast.Expression) ast.Expression {
+	start := p.pos
+	p.expectType(token.Delimiter.LBrace)
+	p.exprLev++
+
+	lit := ast.CompositeLit{Type: typ}
+	for p.tokens[p.pos].Type != token.Delimiter.RBrace && p.tokens[p.pos].Type != token.Special.EOF {
+		elt := p.parseElement()
+		if p.tokens[p.pos].Type == token.Delimiter.Colon {
+			p.pos++
+			value := p.parseElement()
+			elt = ast.KeyValueExpr{Span: p.spanFrom(elt), Key: elt, Value: value}
+		}
+		lit.Elts = append(lit.Elts, elt)
+
+		// a newline after an element needs a comma before it; carry on
+		// as if it were there
+		if tok := p.tokens[p.pos]; tok.Type == token.Delimiter.Semic && tok.Value == "\n" {
+			p.report(tok, diag.CodeSyntax, "missing ',' before newline in composite literal")
+			p.pos++
+			continue
+		}
+		if p.tokens[p.pos].Type != token.Delimiter.Comma {
+			break
+		}
+		p.pos++
+	}
+	p.exprLev--
+	p.expectType(token.Delimiter.RBrace)
+
+	if typ != nil {
+		lit.Span = p.spanFrom(typ)
+	} else {
+		lit.Span = p.spanOf(start, p.pos)
+	}
+	return lit
+}
+
+// parseElement parses one key or value of a composite literal.
+func (p *parser) parseElement() ast.Expression {
+	if p.tokens[p.pos].Type == token.Delimiter.LBrace {
+		return p.parseCompositeLit(nil)
+	}
+	return p.parseExpr()
+}
+
+// addressable reports whether e may appear on the left of an assignment.
+func addressable(e ast.Expression) bool {
+	switch e := e.(type) {
+	case ast.IdentExpr, ast.SelectorExpr, ast.IndexExpr:
+		return true
+	case ast.UnaryExpr:
+		return e.Op.Type == token.Operator.Star
+	case ast.ParenExpr:
+		return addressable(e.X)
+	}
+	return false
+}
+
+func (p *parser) parseCall(fn ast.Expression) ast.Expression {
+	p.expectType(token.Delimiter.LParen)
+	p.exprLev++
+
+	args := []ast.Expression{}
+
+	for p.tokens[p.pos].Type != token.Delimiter.RParen {
+		args = append(args, p.parseExpr())
+		if p.tokens[p.pos].Type != token.Delimiter.Comma {
+			break
+		}
+		p.pos++
+	}
+	p.exprLev--
+
+	p.expectType(token.Delimiter.RParen)
+	return ast.CallExpr{Span: p.spanFrom(fn), Func: fn, Args: args}
+}
+
+// simple statement: a, b := exprs, lhs op= exprs, x++, x-- or a bare expression
+func (p *parser) parseExprOrAssign() ast.Statement {
+	return p.parseSimpleStmt(p.parseExprList())
+}
+
+// parseSimpleStmt finishes a simple statement whose leading expressions
+// are already parsed into lhs.
+func (p *parser) parseSimpleStmt(lhs []ast.Expression) ast.Statement {
+	tok := p.tokens[p.pos]
+	switch {
+	case tok.Type == token.Operator.Define:
+		return p.parseDefine(lhs)
+
+	case isAssignOp(tok.Type):
+		return p.parseAssign(lhs)
+
+	case len(lhs) > 1:
+		p.errorAt(tok, diag.CodeSyntax, "expected := or = or comma, got %s", describe(tok))
+
+	case tok.Type == token.Operator.Inc || tok.Type == token.Operator.Dec:
+		if !addressable(lhs[0]) {
+			p.errorAt(tok, diag.CodeBadAssign, "cannot %s this expression", tok.Value)
+		}
+		p.pos++
+		return ast.IncDecStmt{Span: p.spanFrom(lhs[0]), X: lhs[0], Op: tok.Value}
+	}
+	return ast.ExprStmt{Span: lhs[0].NodeSpan(), Expr: lhs[0]}
+}
+
+// parseExprList parses one or more comma-separated expressions.
+func (p *parser) parseExprList() []ast.Expression {
+	list := []ast.Expression{p.parseExpr()}
+	for p.tokens[p.pos].Type == token.Delimiter.Comma {
+		p.pos++
+		list = append(list, p.parseExpr())
+	}
+	return list
+}
+
+func isAssignOp(typ string) bool {
+	switch typ {
+	case token.Operator.Assign, token.Operator.PlusAssign, token.Operator.MinusAssign,
+		token.Operator.StarAssign, token.Operator.SlashAssign, token.Operator.PercentAssign:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"fox/ast"
+	"fox/diag"
+)
+
+// resolveMethods fills file.Methods from the methods among file.Funcs.
+// It reports receivers that are not defined types of this package,
+// methods declared twice and methods named like a field of their struct
+// type.
+func (p *parser) resolveMethods(file *ast.File) {
+	types := file.TypeSpecs()
+
+	file.Methods = map[string]ast.MethodSet{}
+	declared := map[string]*ast.FuncDecl{}
+	for i := range file.Funcs {
+		f := &file.Funcs[i]
+		if f.Recv == nil {
+			continue
+		}
+		span := f.NameSpan()
+		name, ptr := ast.RecvBase(f.Recv.Type)
+
+		spec, ok := ast.ResolveAlias(types, name)
+		switch {
+		case !ok && types[name] != nil:
+			p.reportSpan(span, diag.CodeBadMethod, "cannot define new methods on non-local type %s", ast.TypeString(types[name].Type))
+			continue
+		case !ok:
+			p.reportSpan(span, diag.CodeBadMethod, "undefined receiver type %s", name)
+			continue
+		}
+		if _, isPtr := spec.Type.(ast.PointerType); isPtr {
+			p.reportSpan(span, diag.CodeBadMethod, "invalid receiver type %s (pointer type)", name)
+			continue
+		}
+		base := spec.Name
+
+		if prev, ok := declared[base+"."+f.Name]; ok {
+			p.reportSpan(span, diag.CodeBadMethod, "method %s.%s already declared at %d:%d",
+				base, f.Name, prev.NamePos.Line, prev.NamePos.Column)
+			continue
+		}
+		if st, ok := spec.Type.(ast.StructType); ok && st.HasField(f.Name) {
+			p.reportSpan(span, diag.CodeBadMethod, "field and method with the same name %s", f.Name)
+			continue
+		}
+		declared[base+"."+f.Name] = f
+
+		set := file.Methods[base]
+		if !ptr {
+			set.Value = append(set.Value, f.Name)
+		}
+		set.Pointer = append(set.Pointer, f.Name)
+		file.Methods[base] = set
+	}
+}
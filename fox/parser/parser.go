@@ -0,0 +1,323 @@
+// Package parser builds the AST of a Fox source file.
+package parser
+
+import (
+	"fox/ast"
+	"fox/diag"
+	"fox/lexer"
+	"fox/token"
+)
+
+// parser holds the state of one parse.
+type parser struct {
+	file   string // name stamped on every span
+	tokens []token.Token
+	pos    int // index of the current token
+	diags  []diag.Diagnostic
+
+	// exprLev is the nesting depth of the expression being parsed. It is
+	// -1 in the header of an if or for, where '{' opens the body and so
+	// cannot start a composite literal; parentheses, brackets and call
+	// arguments lift it back to 0 or more, as in Go. Recovery points
+	// restore it.
+	exprLev int
+}
+
+// ParseFile parses the source of the named file. The AST is returned
+// even when the source has errors, with the broken parts as Bad* nodes;
+// the error is then a diag.List of every problem in source order.
+func ParseFile(name string, src []byte) (*ast.File, error) {
+	tokens, diags := lexer.Tokenize(string(src))
+	p := &parser{file: name, tokens: tokens}
+	file := p.parseFile()
+
+	list := diag.List(append(diags, p.diags...))
+	for i := range list {
+		list[i].SetFile(name)
+	}
+	list.Sort()
+	return file, list.Err()
+}
+
+// ================= Functions =================
+
+func (p *parser) parseFunc() ast.FuncDecl {
+	funcNode := ast.FuncDecl{}
+	start := p.pos
+
+	// func
+	p.expectType(token.Keyword.Func)
+
+	// (recv T) or (recv *T) makes it a method
+	if p.tokens[p.pos].Type == token.Delimiter.LParen {
+		funcNode.Recv = p.parseReceiver()
+	}
+	nameTok := p.expectIdent()
+	funcNode.Name = nameTok.Value
+	funcNode.NamePos = nameTok.Pos()
+	funcNode.NamePos.File = p.file
+
+	// (params)
+	funcNode.Params = p.parseParams()
+
+	// return signature
+	funcNode.Returns = p.parseResults(true)
+
+	// { body }
+	funcNode.Body = p.parseBlock()
+
+	funcNode.Span = p.spanOf(start, p.pos)
+	return funcNode
+}
+
+// (s *T), (s T), or (T) and (*T) when the body does not use it
+func (p *parser) parseReceiver() *ast.ParamDecl {
+	p.expectType(token.Delimiter.LParen)
+	recv := &ast.ParamDecl{}
+	start := p.pos
+	if p.tokens[p.pos].Type == token.Ident.Ident {
+		switch p.tokens[p.pos+1].Type {
+		case token.Delimiter.RParen:
+		case token.Delimiter.Comma:
+			p.errorAt(p.tokens[p.pos+1], diag.CodeSyntax, "method has multiple receivers")
+		default:
+			recv.Name = p.expectIdent().Value
+		}
+	}
+	typTok := p.tokens[p.pos]
+	recv.Type = p.parseType()
+	if base, _ := ast.RecvBase(recv.Type); base == "" {
+		p.errorAt(typTok, diag.CodeBadMethod, "invalid receiver type %s", ast.TypeString(recv.Type))
+	}
+	recv.Span = p.spanOf(start, p.pos)
+
+	if tok := p.tokens[p.pos]; tok.Type == token.Delimiter.Comma {
+		p.errorAt(tok, diag.CodeSyntax, "method has multiple receivers")
+	}
+	p.expectType(token.Delimiter.RParen)
+	return recv
+}
+
+// ================= AST Builder =================
+
+// parseFile parses the tokens of the named file.
+func (p *parser) parseFile() *ast.File {
+	file := &ast.File{}
+	for p.tokens[p.pos].Type != token.Special.EOF {
+		// stray ';'
+		if p.tokens[p.pos].Type == token.Delimiter.Semic {
+			p.pos++
+			continue
+		}
+		p.parseDecl(file)
+	}
+	file.Span = p.spanOf(0, p.pos)
+	p.resolveMethods(file)
+	return file
+}
+
+// parseDecl parses one top-level declaration into file. On a syntax error
+// it skips to the next declaration keyword and records a BadDecl.
+func (p *parser) parseDecl(file *ast.File) {
+	start := p.pos
+	defer func() {
+		if recovered(recover()) {
+			p.exprLev = 0
+			p.syncDecl(start)
+			file.BadDecls = append(file.BadDecls, ast.BadDecl{Span: p.spanOf(start, p.pos)})
+		}
+	}()
+
+	tok := p.tokens[p.pos]
+
+	switch tok.Type {
+	case token.Keyword.Package:
+		file.PackageName = p.parsePackage()
+
+	case token.Keyword.Import:
+		file.Imports = p.parseImport()
+
+	case token.Keyword.Type:
+		file.Types = append(file.Types, p.parseTypeDecl())
+
+	case token.Keyword.Var:
+		file.Vars = append(file.Vars, p.parseVarDecl())
+
+	case token.Keyword.Const:
+		file.Consts = append(file.Consts, p.parseConstDecl())
+
+	case token.Keyword.Func:
+		file.Funcs = append(file.Funcs, p.parseFunc())
+
+	default:
+		p.errorAt(tok, diag.CodeSyntax, "non-declaration statement outside function body")
+	}
+	p.expectSemi()
+}
+
+// ===== Top-Level Parsers =====
+
+func (p *parser) parsePackage() string {
+	p.expectType(token.Keyword.Package)
+	return p.expectIdent().Value
+}
+
+func (p *parser) parseImport() []string {
+	p.expectType(token.Keyword.Import)
+	p.expectType(token.Delimiter.LParen)
+
+	libs := []string{}
+	for p.tokens[p.pos].Value != ")" {
+		pkg := p.expectIdent()
+		libs = append(libs, pkg.Value)
+		if p.tokens[p.pos].Type != token.Delimiter.RParen {
+			p.expectSemi()
+		}
+	}
+	p.expectType(token.Delimiter.RParen)
+	return libs
+}
+
+func (p *parser) parseTypeDecl() ast.TypeDecl {
+	start := p.pos
+	p.expectType(token.Keyword.Type)
+	if p.tokens[p.pos].Type != token.Delimiter.LParen {
+		spec := p.parseTypeSpec()
+		return ast.TypeDecl{Span: p.spanOf(start, p.pos), Specs: []ast.TypeSpec{spec}}
+	}
+	p.pos++
+
+	decl := ast.TypeDecl{Grouped: true}
+	for p.tokens[p.pos].Type != token.Delimiter.RParen && p.tokens[p.pos].Type != token.Special.EOF {
+		decl.Specs = append(decl.Specs, p.parseTypeSpec())
+		if p.tokens[p.pos].Type != token.Delimiter.RParen {
+			p.expectSemi()
+		}
+	}
+	p.expectType(token.Delimiter.RParen)
+	decl.Span = p.spanOf(start, p.pos)
+	return decl
+}
+
+// T U or T = U
+func (p *parser) parseTypeSpec() ast.TypeSpec {
+	start := p.pos
+	spec := ast.TypeSpec{Name: p.expectIdent().Value}
+	if p.tokens[p.pos].Type == token.Operator.Assign {
+		p.pos++
+		spec.Alias = true
+	}
+	spec.Type = p.parseType()
+	spec.Span = p.spanOf(start, p.pos)
+	return spec
+}
+
+func (p *parser) parseVarDecl() ast.VarDecl {
+	start := p.pos
+	p.expectType(token.Keyword.Var)
+	if p.tokens[p.pos].Type != token.Delimiter.LParen {
+		spec := p.parseVarSpec()
+		return ast.VarDecl{Span: p.spanOf(start, p.pos), Specs: []ast.ValueSpec{spec}}
+	}
+	p.pos++
+
+	decl := ast.VarDecl{Grouped: true}
+	for p.tokens[p.pos].Type != token.Delimiter.RParen && p.tokens[p.pos].Type != token.Special.EOF {
+		decl.Specs = append(decl.Specs, p.parseVarSpec())
+		if p.tokens[p.pos].Type != token.Delimiter.RParen {
+			p.expectSemi()
+		}
+	}
+	p.expectType(token.Delimiter.RParen)
+	decl.Span = p.spanOf(start, p.pos)
+	return decl
+}
+
+// a, b T = x, y with the type, the values or both
+func (p *parser) parseVarSpec() ast.ValueSpec {
+	start := p.pos
+	spec := ast.ValueSpec{Names: p.parseIdentList()}
+	switch tok := p.tokens[p.pos]; tok.Type {
+	case token.Delimiter.Semic, token.Delimiter.RParen, token.Special.EOF:
+		p.errorAt(tok, diag.CodeSyntax, "missing variable type or initialization")
+	case token.Operator.Assign:
+	default:
+		spec.Type = p.parseType()
+	}
+	if tok := p.tokens[p.pos]; tok.Type == token.Operator.Assign {
+		p.pos++
+		spec.Values = p.parseExprList()
+		p.checkAssignCount(tok, len(spec.Names), spec.Values)
+	}
+	spec.Span = p.spanOf(start, p.pos)
+	return spec
+}
+
+func (p *parser) parseConstDecl() ast.ConstDecl {
+	start := p.pos
+	p.expectType(token.Keyword.Const)
+	if p.tokens[p.pos].Type != token.Delimiter.LParen {
+		spec := p.parseConstSpec(nil)
+		return ast.ConstDecl{Span: p.spanOf(start, p.pos), Specs: []ast.ValueSpec{spec}}
+	}
+	p.pos++
+
+	decl := ast.ConstDecl{Grouped: true}
+	for p.tokens[p.pos].Type != token.Delimiter.RParen && p.tokens[p.pos].Type != token.Special.EOF {
+		var prev *ast.ValueSpec
+		if n := len(decl.Specs); n > 0 {
+			prev = &decl.Specs[n-1]
+		}
+		decl.Specs = append(decl.Specs, p.parseConstSpec(prev))
+		if p.tokens[p.pos].Type != token.Delimiter.RParen {
+			p.expectSemi()
+		}
+	}
+	p.expectType(token.Delimiter.RParen)
+	decl.Span = p.spanOf(start, p.pos)
+	return decl
+}
+
+// a, b T = x, y; prev is the line before in the same group, whose type
+// and values a bare name list repeats
+func (p *parser) parseConstSpec(prev *ast.ValueSpec) ast.ValueSpec {
+	start := p.pos
+	spec := ast.ValueSpec{Names: p.parseIdentList()}
+	if prev != nil {
+		spec.Iota = prev.Iota + 1
+	}
+
+	tok := p.tokens[p.pos]
+	if tok.Type != token.Operator.Assign && tok.Type != token.Delimiter.Semic && tok.Type != token.Delimiter.RParen {
+		spec.Type = p.parseType()
+		tok = p.tokens[p.pos]
+	}
+	if tok.Type == token.Operator.Assign {
+		p.pos++
+		spec.Values = p.parseExprList()
+	}
+
+	if spec.Values == nil {
+		if spec.Type != nil || prev == nil {
+			p.errorAt(tok, diag.CodeSyntax, "missing init expr for const declaration")
+		}
+		spec.Type, spec.Values, spec.Implicit = prev.Type, prev.Values, true
+	}
+	switch {
+	case len(spec.Values) < len(spec.Names):
+		p.errorAt(tok, diag.CodeAssignMismatch, "missing init expr for const declaration")
+	case len(spec.Values) > len(spec.Names):
+		p.errorAt(tok, diag.CodeAssignMismatch, "extra init expr")
+	}
+	spec.Span = p.spanOf(start, p.pos)
+	return spec
+}
+
+func (p *parser) parseIdentList() []string {
+	names := []string{p.expectIdent().Value}
+	for p.tokens[p.pos].Type == token.Delimiter.Comma {
+		p.pos++
+		names = append(names, p.expectIdent().Value)
+	}
+	return names
+}
@@ -0,0 +1,175 @@
+package parser_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"fox/ast"
+	"fox/diag"
+	"fox/parser"
+)
+
+// parse parses src and returns the file with its error messages.
+func parse(t *testing.T, src string) (*ast.File, []string) {
+	t.Helper()
+	file, err := parser.ParseFile("test.fox", []byte(src))
+	if file == nil {
+		t.Fatalf("no AST for\n%s", src)
+	}
+	var msgs []string
+	if err != nil {
+		var list diag.List
+		if !errors.As(err, &list) {
+			t.Fatalf("error %v is not a diag.List", err)
+		}
+		for _, d := range list {
+			msgs = append(msgs, d.Error())
+		}
+	}
+	return file, msgs
+}
+
+func funcNames(file *ast.File) []string {
+	var names []string
+	for _, f := range file.Funcs {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		errs  []string
+		funcs []string
+	}{
+		{
+			"unclosed body",
+			"package p\n\nfunc a() {\n\tx := 1\n\nfunc b() {\n\ty := 2\n}\n\nfunc c() {}\n",
+			[]string{"test.fox:6:1: expected '}', got 'func'"},
+			[]string{"b", "c"},
+		},
+		{
+			"unclosed struct",
+			"package p\n\ntype S struct {\n\ta int\n\nfunc b() {\n}\n",
+			[]string{"test.fox:6:1: expected identifier, got 'func'"},
+			[]string{"b"},
+		},
+		{
+			"error in each function",
+			"package p\n\nfunc a() {\n\tx := )\n\ty := 1\n}\n\nfunc b() {\n\ty := 1 + )\n}\n\nfunc c() {\n\tz = 1 +\n}\n",
+			[]string{
+				"test.fox:4:7: expected expression, got ')'",
+				"test.fox:9:11: expected expression, got ')'",
+				"test.fox:14:1: expected expression, got '}'",
+			},
+			[]string{"a", "b", "c"},
+		},
+		{
+			"statement at top level",
+			"package p\n\nx := 1\n\nfunc a() {}\n",
+			[]string{"test.fox:3:1: non-declaration statement outside function body"},
+			[]string{"a"},
+		},
+		{
+			"bad package clause",
+			"package\n\nfunc a() {}\n",
+			[]string{"test.fox:3:1: expected identifier, got 'func'"},
+			[]string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, errs := parse(t, tt.src)
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("errors\n%q\nwant\n%q", errs, tt.errs)
+			}
+			if got := funcNames(file); !reflect.DeepEqual(got, tt.funcs) {
+				t.Errorf("functions %q, want %q", got, tt.funcs)
+			}
+		})
+	}
+}
+
+// A statement that fails leaves a BadStmt and the rest of the body.
+func TestRecoveryKeepsStatements(t *testing.T) {
+	file, errs := parse(t, "package p\n\nfunc a() {\n\tx := )\n\ty := 1\n}\n")
+	if len(errs) != 1 {
+		t.Fatalf("errors %q, want one", errs)
+	}
+	body := file.Funcs[0].Body
+	if len(body) != 2 {
+		t.Fatalf("body has %d statements, want 2", len(body))
+	}
+	if _, ok := body[0].(ast.BadStmt); !ok {
+		t.Errorf("first statement is %T, want ast.BadStmt", body[0])
+	}
+	if _, ok := body[1].(ast.DefineStmt); !ok {
+		t.Errorf("second statement is %T, want ast.DefineStmt", body[1])
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	file, errs := parse(t, "package p\n\ntype S struct {\n\tf func()\n\tcb func() \"tag\"\n\tg func(a, b int) (q, r int)\n}\n\nfunc f(a, b int, c string) (q, r int, err error) {\n\treturn\n}\n\nfunc g(int, string) Data, int {\n\treturn\n}\n")
+	if len(errs) != 0 {
+		t.Fatalf("errors %q", errs)
+	}
+
+	sig := func(params []ast.ParamDecl) []string {
+		var list []string
+		for _, param := range params {
+			list = append(list, param.Name+" "+ast.TypeString(param.Type))
+		}
+		return list
+	}
+	if got, want := sig(file.Funcs[0].Params), []string{"a int", "b int", "c string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("f params %q, want %q", got, want)
+	}
+	if got, want := ast.TypeString(file.Funcs[0].ResultType()), "(q int, r int, err error)"; got != want {
+		t.Errorf("f results %s, want %s", got, want)
+	}
+	if got, want := sig(file.Funcs[1].Params), []string{" int", " string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("g params %q, want %q", got, want)
+	}
+
+	fields := file.Types[0].Specs[0].Type.(ast.StructType).Fields
+	if len(fields) != 3 || fields[1].Tag != "tag" {
+		t.Fatalf("fields %+v", fields)
+	}
+	if got, want := ast.TypeString(fields[2].Type), "func(a int, b int) (q int, r int)"; got != want {
+		t.Errorf("g field %s, want %s", got, want)
+	}
+}
+
+func TestSignatureErrors(t *testing.T) {
+	tests := []struct{ src, err string }{
+		{"package p\n\nfunc f(a int b int) {}\n", "test.fox:3:14: expected ')', got identifier b"},
+		{"package p\n\nfunc f(a int, b) {}\n", "test.fox:3:16: mixed named and unnamed parameters"},
+		{"package p\n\nfunc f(a, *T, c int) {}\n", "test.fox:3:20: mixed named and unnamed parameters"},
+	}
+	for _, tt := range tests {
+		if _, errs := parse(t, tt.src); len(errs) != 1 || errs[0] != tt.err {
+			t.Errorf("%q: errors %q, want %q", tt.src, errs, tt.err)
+		}
+	}
+}
+
+// Every position carries the file name, the name of a function included.
+func TestPositionsHaveFile(t *testing.T) {
+	file, _ := parse(t, "package p\n\nfunc (s *S) f(a int) {\n\tx := 1\n}\n")
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			return true
+		}
+		if span := n.NodeSpan(); span.Pos.File != "test.fox" || span.End.File != "test.fox" {
+			t.Errorf("%T spans %+v", n, span)
+		}
+		return true
+	})
+	f := file.Funcs[0]
+	if f.NamePos.File != "test.fox" || f.NameSpan().End.File != "test.fox" {
+		t.Errorf("NameSpan %+v", f.NameSpan())
+	}
+}
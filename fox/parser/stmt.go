@@ -0,0 +1,421 @@
+package parser
+
+import (
+	"fox/ast"
+	"fox/diag"
+	"fox/token"
+)
+
+// parseStatement parses a statement and its terminator. On a syntax error
+// it skips to the next statement and returns a BadStmt in its place.
+func (p *parser) parseStatement() (stmt ast.Statement) {
+	start, lev := p.pos, p.exprLev
+	defer func() {
+		if recovered(recover()) {
+			p.exprLev = lev
+			p.syncStmt()
+			stmt = ast.BadStmt{Span: p.spanOf(start, p.pos)}
+		}
+	}()
+
+	tok := p.tokens[p.pos]
+
+	switch tok.Type {
+	case token.Keyword.Return:
+		stmt = p.parseReturn()
+
+	case token.Keyword.If:
+		stmt = p.parseIf()
+
+	case token.Keyword.For:
+		stmt = p.parseFor()
+
+	case token.Keyword.Var:
+		stmt = p.parseVarDecl()
+
+	case token.Keyword.Const:
+		stmt = p.parseConstDecl()
+
+	case token.Keyword.Switch:
+		stmt = p.parseSwitch()
+
+	case token.Keyword.Break:
+		p.pos++
+		label := p.parseLabelRef()
+		stmt = ast.BreakNode{Span: p.spanOf(start, p.pos), Tok: tok, Label: label}
+
+	case token.Keyword.Continue:
+		p.pos++
+		label := p.parseLabelRef()
+		stmt = ast.ContinueNode{Span: p.spanOf(start, p.pos), Tok: tok, Label: label}
+
+	case token.Keyword.Fallthrough:
+		p.pos++
+		stmt = ast.FallthroughStmt{Span: p.spanOf(start, p.pos), Tok: tok}
+
+	case token.Ident.Ident:
+		// Label: Stmt; the labeled statement ends with its own ';'
+		if p.tokens[p.pos+1].Type == token.Delimiter.Colon {
+			p.pos += 2
+			inner := p.parseStatement()
+			span := p.spanOf(start, start+1)
+			span.End = inner.NodeSpan().End
+			return ast.LabeledStmt{Span: span, Label: tok.Value, Stmt: inner}
+		}
+		stmt = p.parseExprOrAssign()
+
+	default:
+		if token.IsKeyword(tok) {
+			p.errorAt(tok, diag.CodeSyntax, "unexpected %s, expected statement", tok.Value)
+		}
+		stmt = p.parseExprOrAssign()
+	}
+
+	p.expectSemi()
+	return stmt
+}
+
+// Block Parsing
+
+func (p *parser) parseBlock() []ast.Statement {
+	p.expectType(token.Delimiter.LBrace)
+	stmts := p.parseStmtList()
+	p.expectType(token.Delimiter.RBrace)
+	return stmts
+}
+
+// parseStmtList parses statements up to the '}' that ends the block or
+// the case or default that starts the next switch clause.
+func (p *parser) parseStmtList() []ast.Statement {
+	stmts := []ast.Statement{}
+
+	// a declaration keyword means the '}' is missing; leave it to parseFile
+	for !endsStmtList(p.tokens[p.pos]) {
+		// empty statement
+		if p.tokens[p.pos].Type == token.Delimiter.Semic {
+			p.pos++
+			continue
+		}
+		start := p.pos
+		stmts = append(stmts, p.parseStatement())
+
+		// never stall on a token no statement can start with
+		if p.pos == start {
+			p.pos++
+		}
+	}
+	return stmts
+}
+
+func endsStmtList(tok token.Token) bool {
+	switch tok.Type {
+	case token.Delimiter.RBrace, token.Special.EOF, token.Keyword.Case, token.Keyword.Default:
+		return true
+	}
+	return isDeclStart(tok)
+}
+
+//  Statement Parsers
+
+func (p *parser) parseIf() ast.Statement {
+	start := p.pos
+	p.expectType(token.Keyword.If)
+	lev := p.exprLev
+	p.exprLev = -1
+	cond := p.parseExpr()
+	p.exprLev = lev
+	ifStmt := ast.IfStmt{Cond: cond, Then: p.parseBlock()}
+
+	if p.tokens[p.pos].Type == token.Keyword.Else {
+		p.pos++
+		switch tok := p.tokens[p.pos]; tok.Type {
+		case token.Keyword.If:
+			elseIf := p.parseIf().(ast.IfStmt)
+			ifStmt.ElseIf = &elseIf
+		case token.Delimiter.LBrace:
+			ifStmt.Else = p.parseBlock()
+		default:
+			p.errorAt(tok, diag.CodeSyntax, "else must be followed by if or statement block")
+		}
+	}
+	ifStmt.Span = p.spanOf(start, p.pos)
+	return ifStmt
+}
+
+func (p *parser) parseSwitch() ast.Statement {
+	start := p.pos
+	p.expectType(token.Keyword.Switch)
+	sw := ast.SwitchStmt{}
+	if p.tokens[p.pos].Type != token.Delimiter.LBrace {
+		lev := p.exprLev
+		p.exprLev = -1
+		sw.Tag = p.parseExpr()
+		p.exprLev = lev
+	}
+	p.expectType(token.Delimiter.LBrace)
+
+	var dflt *token.Token
+	for p.tokens[p.pos].Type != token.Delimiter.RBrace && p.tokens[p.pos].Type != token.Special.EOF && !isDeclStart(p.tokens[p.pos]) {
+		if p.tokens[p.pos].Type == token.Delimiter.Semic {
+			p.pos++
+			continue
+		}
+		tok := p.tokens[p.pos]
+		clause, ok := p.parseCaseClause()
+		if !ok {
+			continue
+		}
+		if clause.Default {
+			if dflt != nil {
+				p.report(tok, diag.CodeSyntax, "multiple defaults in switch (first at %d:%d)", dflt.Line, dflt.Column)
+			}
+			dflt = &tok
+		}
+		sw.Cases = append(sw.Cases, clause)
+	}
+	p.expectType(token.Delimiter.RBrace)
+
+	p.checkFallthrough(sw.Cases)
+	sw.Span = p.spanOf(start, p.pos)
+	return sw
+}
+
+// parseCaseClause parses case x, y: or default: and the statements after
+// it. An error in the case list skips to the next clause and returns
+// ok false.
+func (p *parser) parseCaseClause() (clause ast.CaseClause, ok bool) {
+	start := p.pos
+	defer func() {
+		if recovered(recover()) {
+			p.syncCase()
+			ok = false
+		}
+	}()
+
+	switch tok := p.tokens[p.pos]; tok.Type {
+	case token.Keyword.Case:
+		p.pos++
+		clause.List = p.parseExprList()
+	case token.Keyword.Default:
+		p.pos++
+		clause.Default = true
+	default:
+		p.errorAt(tok, diag.CodeSyntax, "expected case or default or '}', got %s", describe(tok))
+	}
+	p.expectType(token.Delimiter.Colon)
+
+	clause.Body = p.parseStmtList()
+	clause.Span = p.spanOf(start, p.pos)
+	return clause, true
+}
+
+// checkFallthrough reports a fallthrough that is not the last statement
+// of a case, or that sits in the final case.
+func (p *parser) checkFallthrough(cases []ast.CaseClause) {
+	for i, c := range cases {
+		for j, stmt := range c.Body {
+			ft, ok := stmt.(ast.FallthroughStmt)
+			switch {
+			case !ok:
+			case j != len(c.Body)-1:
+				p.report(ft.Tok, diag.CodeSyntax, "fallthrough statement out of place")
+			case i == len(cases)-1:
+				p.report(ft.Tok, diag.CodeSyntax, "cannot fallthrough final case in switch")
+			}
+		}
+	}
+}
+
+// the optional label after break or continue
+func (p *parser) parseLabelRef() string {
+	if p.tokens[p.pos].Type != token.Ident.Ident {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1].Value
+}
+
+// for { }, for Cond { }, for Init; Cond; Post { } or a range loop
+func (p *parser) parseFor() ast.Statement {
+	start := p.pos
+	p.expectType(token.Keyword.For)
+	forStmt := ast.ForStmt{}
+	lev := p.exprLev
+	p.exprLev = -1
+
+	// INIT, or the condition of a condition-only loop
+	var init ast.Statement
+	switch p.tokens[p.pos].Type {
+	case token.Delimiter.LBrace:
+		p.exprLev = lev
+		forStmt.Body = p.parseBlock()
+		forStmt.Span = p.spanOf(start, p.pos)
+		return forStmt
+
+	case token.Keyword.Range:
+		p.pos++
+		rangeStmt := ast.RangeStmt{X: p.parseExpr()}
+		p.exprLev = lev
+		rangeStmt.Body = p.parseBlock()
+		rangeStmt.Span = p.spanOf(start, p.pos)
+		return rangeStmt
+
+	case token.Delimiter.Semic:
+
+	default:
+		lhs := p.parseExprList()
+		if p.isRangeClause() {
+			rangeStmt := p.parseRangeClause(lhs)
+			p.exprLev = lev
+			rangeStmt.Body = p.parseBlock()
+			rangeStmt.Span = p.spanOf(start, p.pos)
+			return rangeStmt
+		}
+		init = p.parseSimpleStmt(lhs)
+	}
+
+	if p.tokens[p.pos].Type == token.Delimiter.LBrace {
+		cond, ok := init.(ast.ExprStmt)
+		if !ok {
+			p.errorAt(p.tokens[p.pos], diag.CodeSyntax, "expected for loop condition")
+		}
+		forStmt.Cond = cond.Expr
+		p.exprLev = lev
+		forStmt.Body = p.parseBlock()
+		forStmt.Span = p.spanOf(start, p.pos)
+		return forStmt
+	}
+	forStmt.Init = init
+	p.expectType(token.Delimiter.Semic) // use ;
+
+	// CONDITION
+	if p.tokens[p.pos].Type != token.Delimiter.Semic && p.tokens[p.pos].Type != token.Delimiter.LBrace {
+		forStmt.Cond = p.parseExpr()
+	}
+	p.expectType(token.Delimiter.Semic) // use ;
+
+	// POST
+	if p.tokens[p.pos].Type != token.Delimiter.LBrace {
+		forStmt.Post = p.parseExprOrAssign()
+	}
+	p.exprLev = lev
+
+	//  BODY
+	forStmt.Body = p.parseBlock()
+	forStmt.Span = p.spanOf(start, p.pos)
+	return forStmt
+}
+
+func (p *parser) isRangeClause() bool {
+	op := p.tokens[p.pos].Type
+	return (op == token.Operator.Define || op == token.Operator.Assign) && p.tokens[p.pos+1].Type == token.Keyword.Range
+}
+
+// parseRangeClause parses := range X or = range X after the key and
+// value in lhs.
+func (p *parser) parseRangeClause(lhs []ast.Expression) ast.RangeStmt {
+	opTok := p.tokens[p.pos]
+	p.pos += 2
+
+	rangeStmt := ast.RangeStmt{Define: opTok.Type == token.Operator.Define}
+	if len(lhs) > 2 {
+		p.errorAt(opTok, diag.CodeSyntax, "range clause permits at most two iteration variables")
+	}
+	for _, x := range lhs {
+		if _, ok := x.(ast.IdentExpr); rangeStmt.Define && !ok {
+			p.errorAt(opTok, diag.CodeBadAssign, "non-name on left side of :=")
+		}
+		if !addressable(x) {
+			p.errorAt(opTok, diag.CodeBadAssign, "cannot assign to this expression")
+		}
+	}
+	rangeStmt.Key = lhs[0]
+	if len(lhs) == 2 {
+		rangeStmt.Value = lhs[1]
+	}
+	rangeStmt.X = p.parseExpr()
+	return rangeStmt
+}
+
+func (p *parser) parseReturn() ast.Statement {
+	start := p.pos
+	p.expectType(token.Keyword.Return)
+	values := []ast.Expression{}
+
+	if p.tokens[p.pos].Type != token.Delimiter.Semic && p.tokens[p.pos].Type != token.Delimiter.RBrace {
+		values = p.parseExprList()
+	}
+
+	return ast.ReturnStmt{Span: p.spanOf(start, p.pos), RetValues: values}
+}
+
+// Assignment / Definition Parsers
+func (p *parser) parseAssign(lhs []ast.Expression) ast.Statement {
+	opTok := p.tokens[p.pos]
+	if !isAssignOp(opTok.Type) {
+		p.errorAt(opTok, diag.CodeSyntax, "expected assignment operator, got %s", describe(opTok))
+	}
+	for _, x := range lhs {
+		if !addressable(x) {
+			p.errorAt(opTok, diag.CodeBadAssign, "cannot assign to this expression")
+		}
+	}
+	p.pos++
+
+	rhs := p.parseExprList()
+	if opTok.Type != token.Operator.Assign && (len(lhs) > 1 || len(rhs) > 1) {
+		p.errorAt(opTok, diag.CodeAssignMismatch, "assignment operator %s requires single-valued expressions", opTok.Value)
+	}
+	p.checkAssignCount(opTok, len(lhs), rhs)
+
+	return ast.AssignStmt{Span: p.spanFrom(lhs[0]), Lhs: lhs, Op: opTok.Value, Rhs: rhs}
+}
+
+func (p *parser) parseDefine(lhs []ast.Expression) ast.Statement {
+	opTok := p.expectType(token.Operator.Define)
+	fresh := false
+	for _, x := range lhs {
+		id, ok := x.(ast.IdentExpr)
+		if !ok {
+			p.errorAt(opTok, diag.CodeBadAssign, "non-name on left side of :=")
+		}
+		if id.Name != "_" {
+			fresh = true
+		}
+	}
+	if !fresh {
+		p.errorAt(opTok, diag.CodeBadAssign, "no new variables on left side of :=")
+	}
+
+	rhs := p.parseExprList()
+	p.checkAssignCount(opTok, len(lhs), rhs)
+	return ast.DefineStmt{Span: p.spanFrom(lhs[0]), Lhs: lhs, Rhs: rhs}
+}
+
+// checkAssignCount reports n targets and rhs of different lengths, unless
+// rhs is a single call whose results are unpacked into them. It also
+// rejects _ on the right, which has no value.
+func (p *parser) checkAssignCount(opTok token.Token, n int, rhs []ast.Expression) {
+	for _, x := range rhs {
+		if id, ok := x.(ast.IdentExpr); ok && id.Name == "_" {
+			p.errorAt(opTok, diag.CodeSyntax, "cannot use _ as value")
+		}
+	}
+	if n == len(rhs) {
+		return
+	}
+	if _, ok := rhs[0].(ast.CallExpr); ok && len(rhs) == 1 {
+		return
+	}
+	p.errorAt(opTok, diag.CodeAssignMismatch, "assignment mismatch: %d variable%s but %d value%s",
+		n, plural(n), len(rhs), plural(len(rhs)))
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// end
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"fox/ast"
+	"fox/diag"
+	"fox/token"
+)
+
+func (p *parser) parseType() ast.TypeExpr {
+	tok := p.tokens[p.pos]
+	start := p.pos
+	p.checkEOF(tok, "type")
+
+	switch tok.Type {
+	case token.Ident.Ident:
+		p.pos++
+		switch p.tokens[p.pos].Type {
+		case token.Delimiter.Dot:
+			p.pos++
+			name := p.expectIdent().Value
+			return ast.QualifiedType{Span: p.spanOf(start, p.pos), Pkg: tok.Value, Name: name}
+		case token.Delimiter.LBrack:
+			if tok.Value == "own" {
+				p.pos++
+				elem := p.parseType()
+				p.expectType(token.Delimiter.RBrack)
+				return ast.OwnType{Span: p.spanOf(start, p.pos), Elem: elem}
+			}
+		}
+		return ast.NamedType{Span: p.spanOf(start, p.pos), Name: tok.Value}
+
+	case token.Operator.Star:
+		p.pos++
+		if p.tokens[p.pos].Type == token.Operator.Star {
+			p.reportPointerToPointer(p.tokens[p.pos])
+		}
+		elem := p.parseType()
+		return ast.PointerType{Span: p.spanOf(start, p.pos), Elem: elem}
+
+	case token.Delimiter.LBrack:
+		p.pos++
+		if p.tokens[p.pos].Type == token.Delimiter.RBrack {
+			p.pos++
+			elem := p.parseType()
+			return ast.SliceType{Span: p.spanOf(start, p.pos), Elem: elem}
+		}
+		p.exprLev++
+		n := p.parseExpr()
+		p.exprLev--
+		p.expectType(token.Delimiter.RBrack)
+		elem := p.parseType()
+		return ast.ArrayType{Span: p.spanOf(start, p.pos), Len: n, Elem: elem}
+
+	case token.Keyword.Map:
+		p.pos++
+		p.expectType(token.Delimiter.LBrack)
+		key := p.parseType()
+		p.expectType(token.Delimiter.RBrack)
+		value := p.parseType()
+		return ast.MapType{Span: p.spanOf(start, p.pos), Key: key, Value: value}
+
+	case token.Keyword.Struct:
+		return p.parseStructType()
+
+	case token.Keyword.Func:
+		p.pos++
+		fn := ast.FuncType{Params: p.parseParams()}
+		fn.Results = p.parseResults(false)
+		fn.Span = p.spanOf(start, p.pos)
+		return fn
+	}
+
+	p.errorAt(tok, diag.CodeSyntax, "expected type, got %s", describe(tok))
+	return nil
+}
+
+func (p *parser) parseStructType() ast.StructType {
+	start := p.pos
+	p.expectType(token.Keyword.Struct)
+	p.expectType(token.Delimiter.LBrace)
+
+	st := ast.StructType{}
+	for p.tokens[p.pos].Type != token.Delimiter.RBrace && p.tokens[p.pos].Type != token.Special.EOF {
+		st.Fields = append(st.Fields, p.parseField())
+		p.expectSemi()
+	}
+	p.expectType(token.Delimiter.RBrace)
+	st.Span = p.spanOf(start, p.pos)
+	return st
+}
+
+// a, b T, or an embedded T, *T, pkg.T or *pkg.T, each with an optional
+// string tag
+func (p *parser) parseField() ast.FieldDecl {
+	field := ast.FieldDecl{}
+	start := p.pos
+	tok, next := p.tokens[p.pos], p.tokens[p.pos+1]
+	switch {
+	case tok.Type == token.Operator.Star:
+		field.Embedded = true
+	case tok.Type == token.Ident.Ident:
+		switch next.Type {
+		case token.Delimiter.Semic, token.Delimiter.RBrace, token.Delimiter.Dot, token.OtherLiteral.String:
+			field.Embedded = true
+		}
+	}
+
+	if field.Embedded {
+		field.Type = p.parseType()
+		if base, _ := ast.EmbeddedName(field.Type); base == "" {
+			p.errorAt(tok, diag.CodeSyntax, "embedded field type must be a type name")
+		}
+	} else {
+		field.Names = p.parseIdentList()
+		field.Type = p.parseType()
+	}
+
+	if tok := p.tokens[p.pos]; tok.Type == token.OtherLiteral.String {
+		p.pos++
+		field.Tag = tok.Value
+	}
+	field.Span = p.spanOf(start, p.pos)
+	return field
+}
+
+// parseSigList parses a parameter or result list after its '(' up to and
+// including the ')'. Entries are all types, (int, string), or all named,
+// (a int, b string), where like in Go names may share a type: (a, b int).
+// Name is empty for the former.
+func (p *parser) parseSigList() []ast.ReturnSig {
+	var list []ast.ReturnSig
+	named := 0
+	for p.tokens[p.pos].Type != token.Delimiter.RParen {
+		start := p.pos
+		entry := ast.ReturnSig{Type: p.parseType()}
+		if n, ok := entry.Type.(ast.NamedType); ok && !endsSigEntry(p.tokens[p.pos]) {
+			entry.Name = n.Name
+			entry.Type = p.parseType()
+			named++
+		}
+		entry.Span = p.spanOf(start, p.pos)
+		list = append(list, entry)
+
+		if p.tokens[p.pos].Type != token.Delimiter.Comma {
+			break
+		}
+		p.pos++
+	}
+	if named > 0 && !groupNames(list) {
+		p.errorAt(p.tokens[p.pos], diag.CodeSyntax, "mixed named and unnamed parameters")
+	}
+	p.expectType(token.Delimiter.RParen)
+	return list
+}
+
+// groupNames turns the bare names of a named list, a and b in (a, b int),
+// into entries with the type of the next named one. It reports false when
+// an unnamed entry is not a name or nothing named follows it.
+func groupNames(list []ast.ReturnSig) bool {
+	var typ ast.TypeExpr
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Name != "" {
+			typ = list[i].Type
+			continue
+		}
+		n, ok := list[i].Type.(ast.NamedType)
+		if !ok || typ == nil {
+			return false
+		}
+		list[i].Name, list[i].Type = n.Name, typ
+	}
+	return true
+}
+
+func endsSigEntry(tok token.Token) bool {
+	return tok.Type == token.Delimiter.Comma || tok.Type == token.Delimiter.RParen
+}
+
+// parseParams parses a parameter list, ( ... ) included.
+func (p *parser) parseParams() []ast.ParamDecl {
+	p.expectType(token.Delimiter.LParen)
+	var params []ast.ParamDecl
+	for _, param := range p.parseSigList() {
+		params = append(params, ast.ParamDecl{Span: param.Span, Name: param.Name, Type: param.Type})
+	}
+	return params
+}
+
+// parseResults parses the results after a parameter list: nothing, one
+// type, a ( ... ) list, or, in a function declaration where bare is set,
+// Fox's unparenthesized list Data, int ending at the body's '{'. There are
+// results when the next token can start a type.
+func (p *parser) parseResults(bare bool) []ast.ReturnSig {
+	switch tok := p.tokens[p.pos]; {
+	case tok.Type == token.Delimiter.LParen:
+		p.pos++
+		return p.parseSigList()
+	case !startsType(tok):
+		return nil
+	}
+
+	var results []ast.ReturnSig
+	typ := p.parseType()
+	results = append(results, ast.ReturnSig{Span: typ.NodeSpan(), Type: typ})
+	for bare && p.tokens[p.pos].Type == token.Delimiter.Comma {
+		p.pos++
+		typ := p.parseType()
+		results = append(results, ast.ReturnSig{Span: typ.NodeSpan(), Type: typ})
+	}
+	return results
+}
+
+// startsType reports whether tok can begin a type.
+func startsType(tok token.Token) bool {
+	switch tok.Type {
+	case token.Ident.Ident, token.Operator.Star, token.Delimiter.LBrack, token.Delimiter.LParen,
+		token.Keyword.Map, token.Keyword.Struct, token.Keyword.Func:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"fmt"
+
+	"fox/ast"
+	"fox/diag"
+	"fox/token"
+)
+
+// ================= Utilities =================
+
+func (p *parser) expectIdent() token.Token {
+	tok := p.tokens[p.pos]
+	p.checkEOF(tok, "identifier")
+
+	if tok.Type != token.Ident.Ident {
+		p.errorAt(tok, diag.CodeSyntax, "expected identifier, got %s", describe(tok))
+	}
+
+	p.pos++
+	return tok
+}
+
+func (p *parser) expectValue(value string) {
+	tok := p.tokens[p.pos]
+	p.checkEOF(tok, "'"+value+"'")
+
+	if tok.Value != value {
+		p.errorAt(tok, diag.CodeSyntax, "expected '%s', got %s", value, describe(tok))
+	}
+	p.pos++
+}
+
+func (p *parser) expectType(expected string) token.Token {
+	tok := p.tokens[p.pos]
+	p.checkEOF(tok, "'"+expected+"'")
+
+	if tok.Type != expected {
+		p.errorAt(tok, diag.CodeSyntax, "expected '%s', got %s", expected, describe(tok))
+	}
+	p.pos++
+	return tok
+}
+
+func (p *parser) expectKind(kind token.Kind, expectedText string) token.Token {
+	tok := p.tokens[p.pos]
+	p.checkEOF(tok, expectedText)
+
+	if tok.Kind != kind {
+		p.errorAt(tok, diag.CodeSyntax, "expected %s, got %s", expectedText, describe(tok))
+	}
+	p.pos++
+	return tok
+}
+
+// expectSemi consumes a statement terminator, written or inserted at a
+// newline. Like in Go it may be left out before a closing '}'.
+func (p *parser) expectSemi() {
+	tok := p.tokens[p.pos]
+
+	switch tok.Type {
+	case token.Delimiter.Semic:
+		p.pos++
+	case token.Delimiter.RBrace, token.Special.EOF:
+	default:
+		p.errorAt(tok, diag.CodeSyntax, "expected ';' or newline, got %s", describe(tok))
+	}
+}
+
+// ================= Error recovery =================
+
+// isDeclStart reports whether tok begins a declaration that only appears
+// at the top level; var and const may also start a statement.
+func isDeclStart(tok token.Token) bool {
+	switch tok.Type {
+	case token.Keyword.Package, token.Keyword.Import, token.Keyword.Type, token.Keyword.Func:
+		return true
+	}
+	return false
+}
+
+// syncStmt skips to the next statement: past a ';', or up to the '}' that
+// closes the block or a declaration keyword. Nested {...} are skipped whole.
+func (p *parser) syncStmt() {
+	depth := 0
+	for {
+		tok := p.tokens[p.pos]
+		switch {
+		case tok.Type == token.Special.EOF || isDeclStart(tok):
+			return
+		case tok.Type == token.Delimiter.LBrace:
+			depth++
+		case tok.Type == token.Delimiter.RBrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case tok.Type == token.Delimiter.Semic && depth == 0:
+			p.pos++
+			return
+		}
+		p.pos++
+	}
+}
+
+// syncCase skips to the next case or default of a switch, or to its '}'.
+func (p *parser) syncCase() {
+	depth := 0
+	for {
+		tok := p.tokens[p.pos]
+		switch {
+		case tok.Type == token.Special.EOF || isDeclStart(tok):
+			return
+		case (tok.Type == token.Keyword.Case || tok.Type == token.Keyword.Default) && depth == 0:
+			return
+		case tok.Type == token.Delimiter.LBrace:
+			depth++
+		case tok.Type == token.Delimiter.RBrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		}
+		p.pos++
+	}
+}
+
+// syncDecl skips to the next declaration keyword, var and const included.
+// A keyword the failed declaration stopped at is where the next one starts,
+// but the parser moves at least one token past start, where that began.
+func (p *parser) syncDecl(start int) {
+	if p.pos == start && p.tokens[p.pos].Type != token.Special.EOF {
+		p.pos++
+	}
+	for tok := p.tokens[p.pos]; tok.Type != token.Special.EOF; tok = p.tokens[p.pos] {
+		if isDeclStart(tok) || tok.Type == token.Keyword.Var || tok.Type == token.Keyword.Const {
+			return
+		}
+		p.pos++
+	}
+}
+
+// spanOf covers tokens[start:end], or just tokens[start] if that is empty.
+func (p *parser) spanOf(start, end int) token.Span {
+	var span token.Span
+	if end <= start {
+		span = p.tokens[start].Span()
+	} else {
+		span = token.Span{Pos: p.tokens[start].Pos(), End: p.tokens[end-1].End}
+	}
+	span.Pos.File, span.End.File = p.file, p.file
+	return span
+}
+
+// spanFrom covers from the start of x up to the last token consumed.
+func (p *parser) spanFrom(x ast.Node) token.Span {
+	span := x.NodeSpan()
+	span.End = p.tokens[p.pos-1].End
+	span.End.File = p.file
+	return span
+}
+
+// checkEOF reports running out of input where something else was expected.
+func (p *parser) checkEOF(tok token.Token, expected string) {
+	if tok.Type == token.Special.EOF {
+		p.errorAt(tok, diag.CodeUnexpectedEOF, "unexpected end of input, expected %s", expected)
+	}
+}
+
+// describe names a token for error messages.
+func describe(tok token.Token) string {
+	switch {
+	case tok.Type == token.Special.EOF:
+		return "end of input"
+	case tok.Type == token.Delimiter.Semic && tok.Value == "\n":
+		return "newline"
+	case tok.Kind == token.IdentKind:
+		return "identifier " + tok.Value
+	case tok.Type == token.OtherLiteral.String:
+		return fmt.Sprintf("literal %q", tok.Value)
+	case tok.Type == token.OtherLiteral.Rune:
+		return "literal '" + tok.Value + "'"
+	case tok.Kind == token.NumericLiteralKind:
+		return "literal " + tok.Value
+	}
+	return "'" + tok.Value + "'"
+}
+
+func (p *parser) isAssign() bool {
+	return p.tokens[p.pos].Type == token.Ident.Ident &&
+		(p.tokens[p.pos+1].Type == token.Operator.Assign ||
+			p.tokens[p.pos+1].Type == token.Operator.Define)
+}
@@ -0,0 +1,22 @@
+package token
+
+// Position is a point in a source file. Offset is in bytes, Column in
+// runes; both Line and Column start at 1.
+type Position struct {
+	File   string
+	Offset int
+	Line   int
+	Column int
+}
+
+// Span is the source range from Pos up to, not including, End.
+type Span struct {
+	Pos Position
+	End Position
+}
+
+// NodeSpan lets every AST node, all of which embed a Span, satisfy
+// ast.Node.
+func (s Span) NodeSpan() Span {
+	return s
+}
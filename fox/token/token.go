@@ -0,0 +1,266 @@
+// Package token defines the tokens of Fox source and their positions.
+package token
+
+import "fmt"
+
+// Kind is the class of a token.
+type Kind int
+
+const (
+	// Special
+	SpecialKind Kind = iota
+	IdentKind
+	NumericLiteralKind
+	OtherLiteralKind
+	KeywordKind
+	OperatorKind
+	DelimiterKind
+)
+
+// Token
+type Token struct {
+	Kind   Kind
+	Type   string
+	Value  string
+	Offset int // byte offset in the source
+	Line   int
+	Column int      // in runes, 1-based
+	Suffix string   // numeric literals: type suffix such as "u8" or "f32"
+	End    Position // just past the token
+
+	// trivia: comments are not tokens, they hang on the nearest token
+	Leading  []Comment // comments on the lines before the token
+	Trailing []Comment // comments after the token on the same line
+}
+
+// Comment is a `// line` or `/* block */` comment, markers included.
+type Comment struct {
+	Text   string
+	Offset int
+	Line   int
+	Column int
+}
+
+// Specials
+type Specials struct {
+	EOF, Illegal string
+}
+
+type Idents struct {
+	Ident string
+}
+
+type Numerics struct {
+	Int, Float string
+}
+
+type Literals struct {
+	String, Rune, Bool string
+}
+
+type Keywords struct {
+	Package, Import, Type, Struct, Func, Var, Const, If, Else, For,
+	Continue, Break, Return, Switch, Case, Default, Fallthrough, Range, Map string
+}
+
+type Operators struct {
+	Plus, Minus, Star, Slash, Percent, Amp, Pipe, Caret, Shl, Shr, AndNot,
+	Assign, Define, Eq, Neq, Lt, Gt, Lte, Gte, And, Or, Not,
+	PlusAssign, MinusAssign, StarAssign, SlashAssign, PercentAssign, Inc, Dec string
+}
+
+type Delimiters struct {
+	LParen, RParen, LBrace, RBrace, LBrack, RBrack, Comma, Semic, Colon, Dot string
+}
+
+// Values
+var Special = Specials{
+	EOF:     "EOF",
+	Illegal: "ILLEGAL",
+}
+
+var Ident = Idents{
+	Ident: "IDENT",
+}
+
+var NumericLiteral = Numerics{
+	Int:   "INT",
+	Float: "FLOAT",
+}
+
+var OtherLiteral = Literals{
+	String: "STRING",
+	Rune:   "RUNE",
+	Bool:   "BOOL",
+}
+
+var Keyword = Keywords{
+	Package:  "package",
+	Import:   "import",
+	Const:    "const",
+	Type:     "type",
+	Struct:   "struct",
+	Func:     "func",
+	Var:      "var",
+	If:       "if",
+	Else:     "else",
+	For:      "for",
+	Break:    "break",
+	Continue: "continue",
+	Return:   "return",
+
+	Switch:      "switch",
+	Case:        "case",
+	Default:     "default",
+	Fallthrough: "fallthrough",
+	Range:       "range",
+	Map:         "map",
+}
+
+// keywordTable maps each keyword's spelling to its token type.
+var keywordTable = map[string]string{
+	Keyword.Package:  Keyword.Package,
+	Keyword.Import:   Keyword.Import,
+	Keyword.Const:    Keyword.Const,
+	Keyword.Type:     Keyword.Type,
+	Keyword.Struct:   Keyword.Struct,
+	Keyword.Func:     Keyword.Func,
+	Keyword.Var:      Keyword.Var,
+	Keyword.If:       Keyword.If,
+	Keyword.Else:     Keyword.Else,
+	Keyword.For:      Keyword.For,
+	Keyword.Break:    Keyword.Break,
+	Keyword.Continue: Keyword.Continue,
+	Keyword.Return:   Keyword.Return,
+
+	Keyword.Switch:      Keyword.Switch,
+	Keyword.Case:        Keyword.Case,
+	Keyword.Default:     Keyword.Default,
+	Keyword.Fallthrough: Keyword.Fallthrough,
+	Keyword.Range:       Keyword.Range,
+	Keyword.Map:         Keyword.Map,
+}
+
+var Operator = Operators{
+	Plus:    "+",
+	Minus:   "-",
+	Star:    "*",
+	Slash:   "/",
+	Percent: "%",
+	Amp:     "&",
+	Pipe:    "|",
+	Caret:   "^",
+	Shl:     "<<",
+	Shr:     ">>",
+	AndNot:  "&^",
+	Assign:  "=",
+	Define:  ":=",
+	Eq:      "==",
+	Neq:     "!=",
+	Lt:      "<",
+	Gt:      ">",
+	Lte:     "<=",
+	Gte:     ">=",
+	And:     "&&",
+	Or:      "||",
+	Not:     "!",
+
+	PlusAssign:    "+=",
+	MinusAssign:   "-=",
+	StarAssign:    "*=",
+	SlashAssign:   "/=",
+	PercentAssign: "%=",
+	Inc:           "++",
+	Dec:           "--",
+}
+
+var Delimiter = Delimiters{
+	LParen: "(",
+	RParen: ")",
+	LBrace: "{",
+	RBrace: "}",
+	LBrack: "[",
+	RBrack: "]",
+	Comma:  ",",
+	Semic:  ";",
+	Colon:  ":",
+	Dot:    ".",
+}
+
+// ================= Helpers =================
+
+// binary operator precedence, Go's five levels; higher binds tighter
+var binaryPrec = map[string]int{
+	Operator.Or: 1,
+
+	Operator.And: 2,
+
+	Operator.Eq:  3,
+	Operator.Neq: 3,
+	Operator.Lt:  3,
+	Operator.Lte: 3,
+	Operator.Gt:  3,
+	Operator.Gte: 3,
+
+	Operator.Plus:  4,
+	Operator.Minus: 4,
+	Operator.Pipe:  4,
+	Operator.Caret: 4,
+
+	Operator.Star:    5,
+	Operator.Slash:   5,
+	Operator.Percent: 5,
+	Operator.Shl:     5,
+	Operator.Shr:     5,
+	Operator.Amp:     5,
+	Operator.AndNot:  5,
+}
+
+// Precedence returns the precedence of the binary operator typ, or 0 if
+// typ is not one.
+func Precedence(typ string) int {
+	return binaryPrec[typ]
+}
+
+// Lookup returns the token type of the keyword ident, if it is one.
+func Lookup(ident string) (string, bool) {
+	typ, ok := keywordTable[ident]
+	return typ, ok
+}
+
+func IsLiteral(tok Token) bool {
+	return tok.Kind == NumericLiteralKind || tok.Kind == OtherLiteralKind
+}
+
+func IsNumericLiteral(tok Token) bool {
+	return tok.Kind == NumericLiteralKind
+}
+
+func IsKeyword(tok Token) bool {
+	return tok.Kind == KeywordKind
+}
+
+func IsOperator(tok Token) bool {
+	return tok.Kind == OperatorKind
+}
+
+func IsDelimiter(tok Token) bool {
+	return tok.Kind == DelimiterKind
+}
+
+// Pos is where the token starts.
+func (t Token) Pos() Position {
+	return Position{Offset: t.Offset, Line: t.Line, Column: t.Column}
+}
+
+func (t Token) Span() Span {
+	return Span{Pos: t.Pos(), End: t.End}
+}
+
+// String describes t for debugging.
+func (t Token) String() string {
+	return fmt.Sprintf(
+		"{Type: %s, Value: '%s', Line: %d, Column: %d}",
+		t.Type, t.Value, t.Line, t.Column,
+	)
+}